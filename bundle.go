@@ -0,0 +1,79 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// BundleReport is a result of ValidateBundle.
+type BundleReport struct {
+	Certificates   int
+	Keys           int
+	HasExpired     bool // at least one certificate is expired.
+	KeyMatchesCert bool // at least one private key matches one of the certificates.
+	Errors         []*BlockError
+}
+
+// BlockError is an error for a single PEM block in a bundle.
+type BlockError struct {
+	Index int // index of the block, starting from 0.
+	Type  string
+	Err   error
+}
+
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("certutil: block %d (%s): %v", e.Index, e.Type, e.Err)
+}
+
+func (e *BlockError) Unwrap() error { return e.Err }
+
+// ValidateBundle parses all PEM formatted blocks and reports certificates and private keys found.
+// Parse errors are collected per block into the report and do not stop validation.
+// Blocks other than certificates and private keys are ignored.
+// Returns ErrInvalidPEM if no PEM blocks are found.
+func ValidateBundle(s string) (*BundleReport, error) {
+	blocks, err := SplitPEM(s)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BundleReport{}
+	var certs []*x509.Certificate
+	var keys []crypto.PrivateKey
+
+	for i, block := range blocks {
+		switch {
+		case isCertificateBlock(block):
+			cert, err := parseX509Block(block)
+			if err != nil {
+				report.Errors = append(report.Errors, &BlockError{Index: i, Type: block.Type, Err: err})
+				continue
+			}
+			report.Certificates++
+			if IsExpired(cert) {
+				report.HasExpired = true
+			}
+			certs = append(certs, cert)
+
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			key, err := parsePrivateKey(block.Bytes)
+			if err != nil {
+				report.Errors = append(report.Errors, &BlockError{Index: i, Type: block.Type, Err: err})
+				continue
+			}
+			report.Keys++
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		for _, cert := range certs {
+			if ok, _ := KeysMatch(key, cert); ok {
+				report.KeyMatchesCert = true
+			}
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,94 @@
+package certutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestValidateBundle(t *testing.T) {
+	key := readTestdata(t, "rsa.pem")
+	leaf := readTestdata(t, "leaf.pem")
+	chain := readTestdata(t, "chain.pem")
+	malformed := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}))
+	malformedKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}))
+
+	testCases := []struct {
+		name      string
+		input     string
+		certs     int
+		keys      int
+		match     bool
+		expired   bool
+		errBlocks []int
+	}{
+		{"key and chain", leaf + chain + key, 3, 1, true, false, nil},
+		{"mismatched key", leaf + readTestdata(t, "rsa2.pem"), 1, 1, false, false, nil},
+		{"key of another type", leaf + readTestdata(t, "ec256.pem"), 1, 1, false, false, nil},
+		{"missing key", leaf + chain, 3, 0, false, false, nil},
+		{"missing cert", key, 0, 1, false, false, nil},
+		{"expired", expiredCertPEM(t), 1, 0, false, true, nil},
+		{"malformed blocks", malformed + leaf + malformedKey + key, 1, 1, true, false, []int{0, 2}},
+		{"other blocks ignored", readTestdata(t, "dsa_pub.pem") + leaf, 1, 0, false, false, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := ValidateBundle(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Certificates != tc.certs || report.Keys != tc.keys {
+				t.Fatalf("want %d certs and %d keys, got %d and %d", tc.certs, tc.keys, report.Certificates, report.Keys)
+			}
+			if report.KeyMatchesCert != tc.match {
+				t.Fatalf("want KeyMatchesCert %v, got %v", tc.match, report.KeyMatchesCert)
+			}
+			if report.HasExpired != tc.expired {
+				t.Fatalf("want HasExpired %v, got %v", tc.expired, report.HasExpired)
+			}
+
+			if len(report.Errors) != len(tc.errBlocks) {
+				t.Fatalf("want %d errors, got %v", len(tc.errBlocks), report.Errors)
+			}
+			for i, blockErr := range report.Errors {
+				if blockErr.Index != tc.errBlocks[i] {
+					t.Fatalf("want error for block %d, got %d", tc.errBlocks[i], blockErr.Index)
+				}
+				if !IsParseError(blockErr) {
+					t.Fatalf("want parse error, got %v", blockErr)
+				}
+			}
+		})
+	}
+
+	if _, err := ValidateBundle("not a PEM"); !errors.Is(err, ErrInvalidPEM) {
+		t.Fatalf("want ErrInvalidPEM, got %v", err)
+	}
+}
+
+func expiredCertPEM(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "expired"},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     time.Now().Add(-24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,35 @@
+package certutil
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"sync"
+)
+
+// chainCache maps SHA-256 of the input to parsed certificates.
+var chainCache sync.Map // map[[sha256.Size]byte][]*x509.Certificate
+
+// ParseX509ChainCached is like ParseX509Chain but caches parsed certificates by the input hash.
+// Safe for concurrent use. Errors are not cached and the cache is never evicted,
+// so use it only for a limited set of inputs like embedded root bundles.
+//
+// Returned slice is a copy, but certificates are shared between callers and must be treated as read-only.
+func ParseX509ChainCached(s string) ([]*x509.Certificate, error) {
+	key := sha256.Sum256([]byte(s))
+
+	if v, ok := chainCache.Load(key); ok {
+		return copyCerts(v.([]*x509.Certificate)), nil
+	}
+
+	certs, err := ParseX509Chain(s)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := chainCache.LoadOrStore(key, certs)
+	return copyCerts(v.([]*x509.Certificate)), nil
+}
+
+func copyCerts(certs []*x509.Certificate) []*x509.Certificate {
+	return append([]*x509.Certificate(nil), certs...)
+}
@@ -0,0 +1,560 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FingerprintSHA256 returns SHA-256 fingerprint of the certificate
+// as a colon-separated uppercase hex string, same as OpenSSL prints it.
+func FingerprintSHA256(cert *x509.Certificate) string {
+	return colonHex(FingerprintSHA256Bytes(cert))
+}
+
+// FingerprintSHA1 returns SHA-1 fingerprint of the certificate
+// as a colon-separated uppercase hex string, same as OpenSSL prints it.
+func FingerprintSHA1(cert *x509.Certificate) string {
+	return colonHex(FingerprintSHA1Bytes(cert))
+}
+
+// FingerprintSHA256Bytes returns SHA-256 fingerprint of the certificate.
+func FingerprintSHA256Bytes(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.Raw)
+	return sum[:]
+}
+
+// FingerprintSHA1Bytes returns SHA-1 fingerprint of the certificate.
+func FingerprintSHA1Bytes(cert *x509.Certificate) []byte {
+	sum := sha1.Sum(cert.Raw)
+	return sum[:]
+}
+
+// IsExpired reports whether certificate is expired at the current time.
+func IsExpired(cert *x509.Certificate) bool {
+	return time.Now().After(cert.NotAfter)
+}
+
+// IsValidAt reports whether t is within certificate validity period.
+func IsValidAt(cert *x509.Certificate, t time.Time) bool {
+	return !t.Before(cert.NotBefore) && !t.After(cert.NotAfter)
+}
+
+// TimeUntilExpiry returns duration until certificate expires.
+// Negative for already expired certificates.
+func TimeUntilExpiry(cert *x509.Certificate) time.Duration {
+	return time.Until(cert.NotAfter)
+}
+
+// DaysUntilExpiry returns number of whole days until certificate expires, truncated toward zero.
+// Negative for expired certificates, 0 for less than a day before or after expiration.
+func DaysUntilExpiry(cert *x509.Certificate) int {
+	return int(TimeUntilExpiry(cert).Hours() / 24)
+}
+
+// Lifetime returns validity period of the certificate.
+func Lifetime(cert *x509.Certificate) time.Duration {
+	return cert.NotAfter.Sub(cert.NotBefore)
+}
+
+// LifetimeElapsed returns elapsed fraction of the certificate validity period in range [0, 1].
+func LifetimeElapsed(cert *x509.Certificate) float64 {
+	lifetime := Lifetime(cert)
+	if lifetime <= 0 {
+		return 1
+	}
+
+	elapsed := float64(time.Since(cert.NotBefore)) / float64(lifetime)
+	switch {
+	case elapsed < 0:
+		return 0
+	case elapsed > 1:
+		return 1
+	default:
+		return elapsed
+	}
+}
+
+// NeedsRenewal reports whether certificate expires in less than the given duration.
+func NeedsRenewal(cert *x509.Certificate, before time.Duration) bool {
+	return TimeUntilExpiry(cert) < before
+}
+
+// RenewBy returns the time at which the given fraction of the validity period remains.
+// For example 0.33 means the certificate should be renewed when a third of its lifetime is left.
+func RenewBy(cert *x509.Certificate, fraction float64) time.Time {
+	lifetime := Lifetime(cert)
+	return cert.NotAfter.Add(-time.Duration(float64(lifetime) * fraction))
+}
+
+// SANs returns all subject alternative names of the certificate.
+// Duplicates are removed, order is DNS names, IP addresses, emails, URIs.
+func SANs(cert *x509.Certificate) []string {
+	size := len(cert.DNSNames) + len(cert.IPAddresses) + len(cert.EmailAddresses) + len(cert.URIs)
+	names := make([]string, 0, size)
+	seen := make(map[string]struct{}, size)
+
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	for _, name := range cert.DNSNames {
+		add(name)
+	}
+	for _, ip := range cert.IPAddresses {
+		add(ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		add(email)
+	}
+	for _, uri := range cert.URIs {
+		add(uri.String())
+	}
+	return names
+}
+
+// MatchesHostname reports whether certificate is valid for the given host.
+func MatchesHostname(cert *x509.Certificate, host string) bool {
+	return cert.VerifyHostname(host) == nil
+}
+
+// SubjectString returns certificate subject formatted as RFC 2253 string, like "CN=example.com,O=Acme,C=US".
+// Same as `openssl x509 -subject -nameopt RFC2253` prints it.
+func SubjectString(cert *x509.Certificate) string {
+	return formatName(cert.RawSubject, cert.Subject)
+}
+
+// IssuerString returns certificate issuer formatted as RFC 2253 string, like "CN=Root CA,O=Acme,C=US".
+// Same as `openssl x509 -issuer -nameopt RFC2253` prints it.
+func IssuerString(cert *x509.Certificate) string {
+	return formatName(cert.RawIssuer, cert.Issuer)
+}
+
+// formatName keeps the original order of attributes from raw DER, falls back to parsed name.
+func formatName(raw []byte, name pkix.Name) string {
+	var rdn pkix.RDNSequence
+	if rest, err := asn1.Unmarshal(raw, &rdn); err != nil || len(rest) != 0 {
+		return name.String()
+	}
+	return rdn.String()
+}
+
+// FindCertForHost returns the first certificate valid for the given host.
+// Non-expired certificates are preferred.
+func FindCertForHost(certs []*x509.Certificate, host string) (*x509.Certificate, error) {
+	var expired *x509.Certificate
+	for _, cert := range certs {
+		if !MatchesHostname(cert, host) {
+			continue
+		}
+		if !IsExpired(cert) {
+			return cert, nil
+		}
+		if expired == nil {
+			expired = cert
+		}
+	}
+
+	if expired == nil {
+		return nil, fmt.Errorf("certutil: no certificate found for host: %s", host)
+	}
+	return expired, nil
+}
+
+// Newest returns the certificate that expires last, nil for an empty slice.
+func Newest(certs []*x509.Certificate) *x509.Certificate {
+	var newest *x509.Certificate
+	for _, cert := range certs {
+		if newest == nil || cert.NotAfter.After(newest.NotAfter) {
+			newest = cert
+		}
+	}
+	return newest
+}
+
+// SortByExpiry sorts certificates in place by expiration time, soonest to expire first.
+// Order of certificates with the same expiration time is preserved.
+func SortByExpiry(certs []*x509.Certificate) {
+	sort.SliceStable(certs, func(i, j int) bool {
+		return certs[i].NotAfter.Before(certs[j].NotAfter)
+	})
+}
+
+// Dedupe returns certificates without duplicates by DER encoding, order is preserved.
+func Dedupe(certs []*x509.Certificate) []*x509.Certificate {
+	res := make([]*x509.Certificate, 0, len(certs))
+	seen := make(map[string]struct{}, len(certs))
+
+	for _, cert := range certs {
+		if _, ok := seen[string(cert.Raw)]; ok {
+			continue
+		}
+		seen[string(cert.Raw)] = struct{}{}
+		res = append(res, cert)
+	}
+	return res
+}
+
+// FindDuplicates returns groups of certificates with the same DER encoding.
+// Groups are ordered by the first occurrence, certificates without duplicates are not included.
+func FindDuplicates(certs []*x509.Certificate) [][]*x509.Certificate {
+	var groups [][]*x509.Certificate
+	index := make(map[string]int, len(certs))
+
+	for _, cert := range certs {
+		idx, ok := index[string(cert.Raw)]
+		if !ok {
+			idx = len(groups)
+			index[string(cert.Raw)] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], cert)
+	}
+
+	res := groups[:0]
+	for _, group := range groups {
+		if len(group) > 1 {
+			res = append(res, group)
+		}
+	}
+	return res
+}
+
+// CertificatesEqual reports whether 2 certificates are equal.
+// DER encodings are compared in constant time, only length mismatch returns early.
+func CertificatesEqual(a, b *x509.Certificate) bool {
+	return subtle.ConstantTimeCompare(a.Raw, b.Raw) == 1
+}
+
+// CertificateEqualPEM reports whether 2 PEM formatted certificates are equal.
+// See CertificatesEqual for the details.
+func CertificateEqualPEM(aPEM, bPEM string) (bool, error) {
+	a, err := ParseX509(aPEM)
+	if err != nil {
+		return false, err
+	}
+	b, err := ParseX509(bPEM)
+	if err != nil {
+		return false, err
+	}
+	return CertificatesEqual(a, b), nil
+}
+
+// SamePublicKey reports whether 2 certificates have the same public key, error if keys are not comparable.
+// Useful to detect key reuse between renewed or differently issued certificates.
+func SamePublicKey(a, b *x509.Certificate) (bool, error) {
+	return ComparePublicKeys(a.PublicKey, b.PublicKey)
+}
+
+// IsCA reports whether certificate is a certificate authority.
+func IsCA(cert *x509.Certificate) bool {
+	return cert.IsCA && cert.BasicConstraintsValid
+}
+
+// IsSelfSigned reports whether certificate is issued and signed by itself.
+// Returns an error if subject and issuer match but the signature cannot be verified.
+func IsSelfSigned(cert *x509.Certificate) (bool, error) {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false, nil
+	}
+
+	err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SerialHex returns serial number of the certificate as a colon-separated lowercase hex string,
+// same as `openssl x509 -text` prints long serials, like "8f:1a:2b". Zero serial is "00",
+// negative serial is prefixed with "-". Empty if serial number is missing.
+func SerialHex(cert *x509.Certificate) string {
+	if cert.SerialNumber == nil {
+		return ""
+	}
+	b := cert.SerialNumber.Bytes()
+	if len(b) == 0 {
+		return "00"
+	}
+
+	s := strings.ToLower(colonHex(b))
+	if cert.SerialNumber.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// SerialDecimal returns serial number of the certificate as a decimal string.
+// Empty if serial number is missing.
+func SerialDecimal(cert *x509.Certificate) string {
+	if cert.SerialNumber == nil {
+		return ""
+	}
+	return cert.SerialNumber.String()
+}
+
+// SubjectKeyID returns subject key identifier of the certificate
+// as a colon-separated uppercase hex string. Empty if extension is missing.
+func SubjectKeyID(cert *x509.Certificate) string {
+	return colonHex(cert.SubjectKeyId)
+}
+
+// AuthorityKeyID returns authority key identifier of the certificate
+// as a colon-separated uppercase hex string. Empty if extension is missing.
+func AuthorityKeyID(cert *x509.Certificate) string {
+	return colonHex(cert.AuthorityKeyId)
+}
+
+// ComputeSubjectKeyID returns SHA-1 hash of the subjectPublicKey BIT STRING
+// as described in RFC 5280, section 4.2.1.2, method 1.
+func ComputeSubjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum(spki.PublicKey.Bytes)
+	return sum[:], nil
+}
+
+// KeyID returns SHA-256 of the PKIX encoded public key as a lowercase hex string.
+// Key can also be a private key or a *x509.Certificate, ID is the same for all of them.
+func KeyID(pub crypto.PublicKey) (string, error) {
+	key, err := toPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("certutil: marshal public key: %w", err)
+	}
+
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// OCSPServers returns OCSP responder URLs of the certificate.
+// Duplicates and malformed URLs are removed.
+func OCSPServers(cert *x509.Certificate) []string {
+	return uniqueURLs(cert.OCSPServer)
+}
+
+// CRLDistributionPoints returns CRL distribution point URLs of the certificate.
+// Duplicates and malformed URLs are removed.
+func CRLDistributionPoints(cert *x509.Certificate) []string {
+	return uniqueURLs(cert.CRLDistributionPoints)
+}
+
+// IssuingCertificateURLs returns URLs of the certificate issuer.
+// Duplicates and malformed URLs are removed.
+func IssuingCertificateURLs(cert *x509.Certificate) []string {
+	return uniqueURLs(cert.IssuingCertificateURL)
+}
+
+// IsWeakSignature reports whether certificate is signed with a broken hash (MD2, MD5 or SHA-1).
+func IsWeakSignature(cert *x509.Certificate) bool {
+	switch cert.SignatureAlgorithm {
+	case x509.MD2WithRSA, x509.MD5WithRSA,
+		x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return true
+	default:
+		return false
+	}
+}
+
+// SignatureHash returns hash function used in the certificate signature.
+// Reports false for unknown algorithms and for Ed25519 which does not pre-hash the message.
+func SignatureHash(cert *x509.Certificate) (crypto.Hash, bool) {
+	switch cert.SignatureAlgorithm {
+	case x509.MD2WithRSA:
+		return crypto.Hash(0), false // MD2 is not available in crypto.Hash.
+	case x509.MD5WithRSA:
+		return crypto.MD5, true
+	case x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return crypto.SHA1, true
+	case x509.SHA256WithRSA, x509.DSAWithSHA256, x509.ECDSAWithSHA256, x509.SHA256WithRSAPSS:
+		return crypto.SHA256, true
+	case x509.SHA384WithRSA, x509.ECDSAWithSHA384, x509.SHA384WithRSAPSS:
+		return crypto.SHA384, true
+	case x509.SHA512WithRSA, x509.ECDSAWithSHA512, x509.SHA512WithRSAPSS:
+		return crypto.SHA512, true
+	default:
+		return crypto.Hash(0), false
+	}
+}
+
+// SignatureAlgorithmName returns human-readable name of the certificate signature algorithm.
+func SignatureAlgorithmName(cert *x509.Certificate) string {
+	if name, ok := sigAlgoNames[cert.SignatureAlgorithm]; ok {
+		return name
+	}
+	return cert.SignatureAlgorithm.String()
+}
+
+var sigAlgoNames = map[x509.SignatureAlgorithm]string{
+	x509.MD2WithRSA:       "MD2 with RSA",
+	x509.MD5WithRSA:       "MD5 with RSA",
+	x509.SHA1WithRSA:      "SHA-1 with RSA",
+	x509.SHA256WithRSA:    "SHA-256 with RSA",
+	x509.SHA384WithRSA:    "SHA-384 with RSA",
+	x509.SHA512WithRSA:    "SHA-512 with RSA",
+	x509.DSAWithSHA1:      "DSA with SHA-1",
+	x509.DSAWithSHA256:    "DSA with SHA-256",
+	x509.ECDSAWithSHA1:    "ECDSA with SHA-1",
+	x509.ECDSAWithSHA256:  "ECDSA with SHA-256",
+	x509.ECDSAWithSHA384:  "ECDSA with SHA-384",
+	x509.ECDSAWithSHA512:  "ECDSA with SHA-512",
+	x509.SHA256WithRSAPSS: "RSA-PSS with SHA-256",
+	x509.SHA384WithRSAPSS: "RSA-PSS with SHA-384",
+	x509.SHA512WithRSAPSS: "RSA-PSS with SHA-512",
+	x509.PureEd25519:      "Ed25519",
+}
+
+// KeyUsages returns human-readable names of the certificate key usages, like "Digital Signature".
+// Same names and order as OpenSSL prints them.
+func KeyUsages(cert *x509.Certificate) []string {
+	var names []string
+	for _, ku := range keyUsageNames {
+		if cert.KeyUsage&ku.usage != 0 {
+			names = append(names, ku.name)
+		}
+	}
+	return names
+}
+
+// ExtKeyUsages returns human-readable names of the certificate extended key usages,
+// like "Server Authentication". Unknown usages are returned as dotted OIDs.
+func ExtKeyUsages(cert *x509.Certificate) []string {
+	names := make([]string, 0, len(cert.ExtKeyUsage)+len(cert.UnknownExtKeyUsage))
+	for _, u := range cert.ExtKeyUsage {
+		name, ok := extKeyUsageNames[u]
+		if !ok {
+			name = fmt.Sprintf("Unknown (%d)", u)
+		}
+		names = append(names, name)
+	}
+	for _, oid := range cert.UnknownExtKeyUsage {
+		names = append(names, oid.String())
+	}
+	return names
+}
+
+// HasExtKeyUsage reports whether certificate has the given extended key usage.
+// x509.ExtKeyUsageAny matches any usage.
+func HasExtKeyUsage(cert *x509.Certificate, u x509.ExtKeyUsage) bool {
+	for _, v := range cert.ExtKeyUsage {
+		if v == u || v == x509.ExtKeyUsageAny {
+			return true
+		}
+	}
+	return false
+}
+
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "Digital Signature"},
+	{x509.KeyUsageContentCommitment, "Non Repudiation"},
+	{x509.KeyUsageKeyEncipherment, "Key Encipherment"},
+	{x509.KeyUsageDataEncipherment, "Data Encipherment"},
+	{x509.KeyUsageKeyAgreement, "Key Agreement"},
+	{x509.KeyUsageCertSign, "Certificate Sign"},
+	{x509.KeyUsageCRLSign, "CRL Sign"},
+	{x509.KeyUsageEncipherOnly, "Encipher Only"},
+	{x509.KeyUsageDecipherOnly, "Decipher Only"},
+}
+
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "Any Extended Key Usage",
+	x509.ExtKeyUsageServerAuth:                     "Server Authentication",
+	x509.ExtKeyUsageClientAuth:                     "Client Authentication",
+	x509.ExtKeyUsageCodeSigning:                    "Code Signing",
+	x509.ExtKeyUsageEmailProtection:                "E-mail Protection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "IPSec End System",
+	x509.ExtKeyUsageIPSECTunnel:                    "IPSec Tunnel",
+	x509.ExtKeyUsageIPSECUser:                      "IPSec User",
+	x509.ExtKeyUsageTimeStamping:                   "Time Stamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSP Signing",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "Microsoft Server Gated Crypto",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "Netscape Server Gated Crypto",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "Microsoft Commercial Code Signing",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "Microsoft Kernel Code Signing",
+}
+
+// Extension returns raw DER value of the certificate extension with the given OID.
+// Reports false if extension is missing.
+func Extension(cert *x509.Certificate, oid asn1.ObjectIdentifier) ([]byte, bool) {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oid) {
+			return ext.Value, true
+		}
+	}
+	return nil, false
+}
+
+// ExtensionOIDs returns dotted OIDs of all certificate extensions in order, like "2.5.29.17".
+func ExtensionOIDs(cert *x509.Certificate) []string {
+	oids := make([]string, 0, len(cert.Extensions))
+	for _, ext := range cert.Extensions {
+		oids = append(oids, ext.Id.String())
+	}
+	return oids
+}
+
+// colonHex formats bytes as AB:CD:EF.
+func colonHex(b []byte) string {
+	const digits = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for i, v := range b {
+		if i > 0 {
+			sb.WriteByte(':')
+		}
+		sb.WriteByte(digits[v>>4])
+		sb.WriteByte(digits[v&0x0f])
+	}
+	return sb.String()
+}
+
+// uniqueURLs returns absolute URLs without duplicates, order is preserved.
+func uniqueURLs(urls []string) []string {
+	res := make([]string, 0, len(urls))
+	seen := make(map[string]struct{}, len(urls))
+
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || !u.IsAbs() {
+			continue
+		}
+		if _, ok := seen[raw]; ok {
+			continue
+		}
+		seen[raw] = struct{}{}
+		res = append(res, raw)
+	}
+	return res
+}
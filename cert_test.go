@@ -0,0 +1,89 @@
+package certutil
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+)
+
+func TestIsSelfSigned(t *testing.T) {
+	testCases := []struct {
+		file    string
+		want    bool
+		wantErr bool
+		isCA    bool
+	}{
+		{"root.pem", true, false, true},
+		{"int.pem", false, false, true},
+		{"leaf.pem", false, false, false},
+		{"root_badsig.pem", false, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			cert := mustCertificate(t, tc.file)
+
+			got, err := IsSelfSigned(cert)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+			if got := IsCA(cert); got != tc.isCA {
+				t.Fatalf("IsCA: want %v, got %v", tc.isCA, got)
+			}
+		})
+	}
+}
+
+func TestSerialHex(t *testing.T) {
+	// want values are from `openssl x509 -serial` and `openssl x509 -text`.
+	testCases := []struct {
+		file    string
+		want    string
+		decimal string
+	}{
+		{"leaf.pem", "03", "3"},
+		{"serial_128.pem", "80", "128"},
+		{"serial_u64.pem", "ff:ff:ff:ff:ff:ff:ff:ff", "18446744073709551615"},
+		{"serial_high.pem", "8f:1a:2b:3c:4d:5e:6f:70:81:92", "675781137846385939349906"},
+	}
+
+	for _, tc := range testCases {
+		cert := mustCertificate(t, tc.file)
+		if got := SerialHex(cert); got != tc.want {
+			t.Errorf("%s: want %q, got %q", tc.file, tc.want, got)
+		}
+		if got := SerialDecimal(cert); got != tc.decimal {
+			t.Errorf("%s: want %q, got %q", tc.file, tc.decimal, got)
+		}
+	}
+
+	// Go rejects negative serials on parse, so these are built directly.
+	serials := []struct {
+		serial *big.Int
+		want   string
+	}{
+		{big.NewInt(0), "00"},
+		{big.NewInt(-1), "-01"},
+		{big.NewInt(-5), "-05"},
+		{new(big.Int).Neg(new(big.Int).SetBytes([]byte{0x8f, 0x1a, 0x2b})), "-8f:1a:2b"},
+		{nil, ""},
+	}
+	for _, tc := range serials {
+		cert := &x509.Certificate{SerialNumber: tc.serial}
+		if got := SerialHex(cert); got != tc.want {
+			t.Errorf("%v: want %q, got %q", tc.serial, tc.want, got)
+		}
+	}
+}
+
+func mustCertificate(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	cert, err := ParseX509(readTestdata(t, name))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return cert
+}
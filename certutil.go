@@ -5,111 +5,630 @@ import (
 	"crypto/dsa"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrInvalidPEM is returned when input does not contain a PEM formatted block.
+	ErrInvalidPEM = errors.New("certutil: invalid PEM")
+
+	// ErrInvalidSignature is returned when signature verification fails.
+	ErrInvalidSignature = errors.New("certutil: invalid signature")
+
+	// ErrIncorrectPassword is returned when PKCS #12 data cannot be decrypted with the given password.
+	ErrIncorrectPassword = errors.New("certutil: incorrect password")
+
+	// ErrPointNotOnCurve is returned when ECDSA public point is not on the key curve.
+	ErrPointNotOnCurve = errors.New("certutil: point is not on curve")
 )
 
 // ParseRSA private key from a PEM formatted block.
+// PKCS #1 is tried first, then PKCS #8.
 func ParseRSA(s string) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(s))
-	if block == nil {
-		return nil, errors.New("invalid PEM")
+	block, err := decodePEMType(s, "RSA PRIVATE KEY", "PRIVATE KEY")
+	if err != nil {
+		return nil, err
 	}
-	return x509.ParsePKCS1PrivateKey(block.Bytes)
+	return parseRSA(block.Bytes)
 }
 
 // ParseECDSA private key from a PEM formatted block.
+// SEC 1 is tried first, then PKCS #8.
 func ParseECDSA(s string) (*ecdsa.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(s))
-	if block == nil {
-		return nil, errors.New("invalid PEM")
+	block, err := decodePEMType(s, "EC PRIVATE KEY", "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parseECDSA(block.Bytes)
+}
+
+// ParseECDSAOnCurve private key from a PEM formatted block.
+// Returns an error if the key curve is not one of the allowed curves.
+func ParseECDSAOnCurve(s string, allowed ...elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	key, err := ParseECDSA(s)
+	if err != nil {
+		return nil, err
+	}
+
+	name := CurveName(key)
+	for _, curve := range allowed {
+		if curve.Params().Name == name {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("certutil: curve is not allowed: %s", name)
+}
+
+// ParseECDSAWithCurve private key from a PEM formatted block assuming the given curve.
+// When ParseECDSA fails, for example on SEC 1 key with missing curve OID,
+// the key is reconstructed from the raw private scalar on the given curve.
+//
+// This is a best-effort fallback for malformed keys, not a replacement for ParseECDSA.
+// Returns an error if the key is on a different curve or the embedded public key does not match.
+func ParseECDSAWithCurve(s string, curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	block, err := decodePEMType(s, "EC PRIVATE KEY", "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parseECDSA(block.Bytes)
+	if err == nil {
+		if key.Curve.Params().Name != curve.Params().Name {
+			return nil, fmt.Errorf("certutil: unexpected curve %s, wanted %s", CurveName(key), curve.Params().Name)
+		}
+		return key, nil
+	}
+
+	var ecKey struct {
+		Version    int
+		PrivateKey []byte
+		Curve      asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+		PublicKey  asn1.BitString        `asn1:"optional,explicit,tag:1"`
+	}
+	if _, errRaw := asn1.Unmarshal(block.Bytes, &ecKey); errRaw != nil {
+		return nil, err
+	}
+
+	params := curve.Params()
+	d := new(big.Int).SetBytes(ecKey.PrivateKey)
+	if len(ecKey.PrivateKey) > (params.BitSize+7)/8 || d.Sign() <= 0 || d.Cmp(params.N) >= 0 {
+		return nil, parseErrorf("invalid ECDSA private key")
+	}
+
+	key = &ecdsa.PrivateKey{D: d}
+	key.Curve = curve
+	key.X, key.Y = curve.ScalarBaseMult(d.FillBytes(make([]byte, (params.BitSize+7)/8)))
+
+	if len(ecKey.PublicKey.Bytes) > 0 {
+		x, y := elliptic.Unmarshal(curve, ecKey.PublicKey.Bytes)
+		if x == nil || x.Cmp(key.X) != 0 || y.Cmp(key.Y) != 0 {
+			return nil, parseErrorf("ECDSA public key does not match private key")
+		}
+	}
+	return key, nil
+}
+
+// ParseECDSAValidated private key from a PEM formatted block.
+// Returns ErrPointNotOnCurve if the public point is not on the key curve.
+//
+// The public point of a parsed private key is always computed from the private scalar,
+// so the check is defensive only. Public points from untrusted input are validated
+// by ParsePublicKey and ParsePublicKeyJWK.
+func ParseECDSAValidated(s string) (*ecdsa.PrivateKey, error) {
+	key, err := ParseECDSA(s)
+	if err != nil {
+		return nil, err
+	}
+	if !key.Curve.IsOnCurve(key.X, key.Y) {
+		return nil, ErrPointNotOnCurve
+	}
+	return key, nil
+}
+
+// ParseRSAValidated private key from a PEM formatted block.
+// Returns an error if the key is not internally consistent, see rsa.PrivateKey.Validate.
+func ParseRSAValidated(s string) (*rsa.PrivateKey, error) {
+	key, err := ParseRSA(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := key.Validate(); err != nil {
+		return nil, safeError("validate RSA", err)
+	}
+	return key, nil
+}
+
+// ParseRSAWithPassword private key from a PEM formatted block encrypted with a password.
+// Unencrypted blocks are parsed as is.
+//
+// Legacy PEM encryption (RFC 1423) is insecure by design and supported only
+// because such keys are still used in the wild.
+// A wrong password usually results in x509.IncorrectPasswordError, but because the scheme has no
+// integrity check it sometimes produces valid padding and the failure is reported as a parse error.
+func ParseRSAWithPassword(s, password string) (*rsa.PrivateKey, error) {
+	block, err := decodePEMType(s, "RSA PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := decryptBlock(block, password)
+	if err != nil {
+		return nil, safeError("decrypt RSA", err)
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(der)
+	if err != nil {
+		return nil, safeError("parse RSA", err)
+	}
+	return key, nil
+}
+
+// ParseECDSAWithPassword private key from a PEM formatted block encrypted with a password.
+// See ParseRSAWithPassword for the details.
+func ParseECDSAWithPassword(s, password string) (*ecdsa.PrivateKey, error) {
+	block, err := decodePEMType(s, "EC PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := decryptBlock(block, password)
+	if err != nil {
+		return nil, safeError("decrypt ECDSA", err)
+	}
+
+	key, err := x509.ParseECPrivateKey(der)
+	if err != nil {
+		return nil, safeError("parse ECDSA", err)
+	}
+	return key, nil
+}
+
+// ParseEd25519 private key from a PEM formatted block.
+func ParseEd25519(s string) (ed25519.PrivateKey, error) {
+	block, err := decodePEMType(s, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parseEd25519(block.Bytes)
+}
+
+// ParseDSA private key from a PEM formatted block.
+// OpenSSL traditional ("DSA PRIVATE KEY") encoding is tried first, then PKCS #8.
+func ParseDSA(s string) (*dsa.PrivateKey, error) {
+	block, err := decodePEMType(s, "DSA PRIVATE KEY", "PRIVATE KEY")
+	if err != nil {
+		return nil, err
 	}
-	return x509.ParseECPrivateKey(block.Bytes)
+	return parseDSA(block.Bytes)
+}
+
+// ParsePrivateKey RSA, ECDSA, Ed25519 and DSA private keys from a PEM formatted block.
+// PKCS #8, PKCS #1, SEC 1 and OpenSSL DSA encodings are tried in that order.
+func ParsePrivateKey(s string) (crypto.PrivateKey, error) {
+	block, err := DecodePEM(s)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(block.Bytes)
 }
 
 // ParseX509 certificate from a PEM formatted block.
+// OpenSSL "TRUSTED CERTIFICATE" blocks are supported, trust settings are ignored.
 func ParseX509(s string) (*x509.Certificate, error) {
-	block, _ := pem.Decode([]byte(s))
-	if block == nil {
-		return nil, errors.New("invalid PEM")
+	block, err := decodePEMType(s, "CERTIFICATE", "TRUSTED CERTIFICATE")
+	if err != nil {
+		return nil, err
 	}
-	return x509.ParseCertificate(block.Bytes)
+	return parseX509Block(block)
 }
 
-// ParsePublicKey RSA and ECDSA public keys from a PEM formatted block.
-func ParsePublicKey(s string) (crypto.PublicKey, error) {
-	block, _ := pem.Decode([]byte(s))
-	if block == nil {
-		return nil, errors.New("data does not contain any valid public keys")
-	}
+// ParseX509FromDER certificate from DER encoded bytes.
+func ParseX509FromDER(der []byte) (*x509.Certificate, error) {
+	return parseX509(der)
+}
 
-	rawKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+// ParseX509FromBase64 certificate from base64 encoded DER without PEM armor.
+// Padding is optional, whitespace and line breaks are ignored.
+func ParseX509FromBase64(s string) (*x509.Certificate, error) {
+	s = strings.Join(strings.Fields(s), "")
+
+	enc := base64.StdEncoding
+	if len(s)%4 != 0 {
+		enc = base64.RawStdEncoding
+	}
+	der, err := enc.DecodeString(s)
 	if err != nil {
-		cert, err := x509.ParseCertificate(block.Bytes)
+		return nil, safeError("decode base64", err)
+	}
+	return ParseX509FromDER(der)
+}
+
+// ParseX509Chain certificates from PEM formatted blocks.
+// Non-certificate blocks are skipped, order of certificates is preserved.
+func ParseX509Chain(s string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := []byte(NormalizePEM(s))
+	for {
+		cert, next, err := NextCertificate(rest)
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
 			return nil, err
 		}
-		rawKey = cert.PublicKey
+		certs = append(certs, cert)
+		rest = next
 	}
 
-	switch key := rawKey.(type) {
+	if len(certs) == 0 {
+		return nil, parseErrorf("no certificates found")
+	}
+	return certs, nil
+}
+
+// ParseKeyPair private key and certificate from PEM formatted blocks in any order.
+// The first private key and the first certificate are used, they must match each other.
+func ParseKeyPair(s string) (crypto.PrivateKey, *x509.Certificate, error) {
+	var priv crypto.PrivateKey
+	var cert *x509.Certificate
+
+	rest := []byte(NormalizePEM(s))
+	for priv == nil || cert == nil {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		var err error
+		switch {
+		case isCertificateBlock(block) && cert == nil:
+			cert, err = parseX509Block(block)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY") && priv == nil:
+			priv, err = parsePrivateKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case priv == nil && cert == nil:
+		return nil, nil, parseErrorf("no private key and certificate found")
+	case priv == nil:
+		return nil, nil, errors.New("certutil: no private key found")
+	case cert == nil:
+		return nil, nil, errors.New("certutil: no certificate found")
+	}
+
+	ok, err := KeysMatch(priv, cert)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, errors.New("certutil: private key does not match certificate")
+	}
+	return priv, cert, nil
+}
+
+// NextCertificate parses the first certificate from PEM formatted data and returns the remaining data.
+// Non-certificate blocks are skipped. Returns io.EOF when there are no more certificates.
+func NextCertificate(data []byte) (*x509.Certificate, []byte, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, nil, io.EOF
+		}
+		if !isCertificateBlock(block) {
+			continue
+		}
+
+		cert, err := parseX509Block(block)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cert, data, nil
+	}
+}
+
+// ParseCSR certificate signing request from a PEM formatted block.
+// Signature of the request is verified.
+func ParseCSR(s string) (*x509.CertificateRequest, error) {
+	block, err := decodePEMType(s, "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, safeError("parse CSR", err)
+	}
+	if err := req.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("certutil: check CSR signature: %w", err)
+	}
+	return req, nil
+}
+
+// ParsePublicKey RSA, ECDSA, Ed25519 and DSA public keys from a PEM formatted block.
+// PKIX, PKCS #1 ("RSA PUBLIC KEY") and certificate blocks are supported.
+func ParsePublicKey(s string) (crypto.PublicKey, error) {
+	block, err := DecodePEM(s)
+	if err != nil {
+		return nil, err
+	}
+	return PublicKeyFromDER(block.Bytes)
+}
+
+// PublicKeyFromDER RSA, ECDSA, Ed25519 and DSA public keys from DER encoded bytes.
+// Same encodings as in ParsePublicKey are supported.
+func PublicKeyFromDER(der []byte) (crypto.PublicKey, error) {
+	return parsePublicKey(der)
+}
+
+// ParseRSAPublicKey RSA public key from a PEM formatted block.
+// PKIX, PKCS #1 ("RSA PUBLIC KEY") and certificate blocks are supported.
+func ParseRSAPublicKey(s string) (*rsa.PublicKey, error) {
+	pub, err := ParsePublicKey(s)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("certutil: not an RSA public key: %T", pub)
+	}
+	return key, nil
+}
+
+// ParseECDSAPublicKey ECDSA public key from a PEM formatted block.
+// PKIX and certificate blocks are supported.
+func ParseECDSAPublicKey(s string) (*ecdsa.PublicKey, error) {
+	pub, err := ParsePublicKey(s)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("certutil: not an ECDSA public key: %T", pub)
+	}
+	return key, nil
+}
+
+// ComparePublicKeys reports whether 2 public keys are equal, error if not comparable.
+func ComparePublicKeys(key1, key2 crypto.PublicKey) (bool, error) {
+	if reflect.TypeOf(key1) != reflect.TypeOf(key2) {
+		return false, fmt.Errorf("certutil: key types do not match: %T and %T", key1, key2)
+	}
+
+	// all standard public keys (except DSA) implement this since Go 1.15.
+	if key1, ok := key1.(interface{ Equal(crypto.PublicKey) bool }); ok {
+		return key1.Equal(key2), nil
+	}
+
+	switch key1 := key1.(type) {
+	case *dsa.PublicKey:
+		key2 := key2.(*dsa.PublicKey)
+		cmp := key1.P.Cmp(key2.P) == 0 &&
+			key1.Q.Cmp(key2.Q) == 0 &&
+			key1.G.Cmp(key2.G) == 0 &&
+			key1.Y.Cmp(key2.Y) == 0
+		return cmp, nil
+
+	default:
+		return false, fmt.Errorf("certutil: unsupported key type: %T", key1)
+	}
+}
+
+// DiffPublicKeys returns names of attributes that differ between 2 public keys, empty if keys are equal.
+// Names are "modulus" and "exponent" for RSA, "curve", "X" and "Y" for ECDSA, "key" for Ed25519
+// and "P", "Q", "G", "Y" for DSA. Key material itself is not included.
+func DiffPublicKeys(key1, key2 crypto.PublicKey) ([]string, error) {
+	if reflect.TypeOf(key1) != reflect.TypeOf(key2) {
+		return nil, fmt.Errorf("certutil: key types do not match: %T and %T", key1, key2)
+	}
+
+	var diff []string
+	add := func(name string, equal bool) {
+		if !equal {
+			diff = append(diff, name)
+		}
+	}
+
+	switch key1 := key1.(type) {
 	case *rsa.PublicKey:
-		return key, nil
+		key2 := key2.(*rsa.PublicKey)
+		add("modulus", key1.N.Cmp(key2.N) == 0)
+		add("exponent", key1.E == key2.E)
+
 	case *ecdsa.PublicKey:
-		return key, nil
+		key2 := key2.(*ecdsa.PublicKey)
+		add("curve", key1.Curve == key2.Curve)
+		add("X", key1.X.Cmp(key2.X) == 0)
+		add("Y", key1.Y.Cmp(key2.Y) == 0)
+
 	case ed25519.PublicKey:
-		return key, nil
+		add("key", key1.Equal(key2))
+
+	case *dsa.PublicKey:
+		key2 := key2.(*dsa.PublicKey)
+		add("P", key1.P.Cmp(key2.P) == 0)
+		add("Q", key1.Q.Cmp(key2.Q) == 0)
+		add("G", key1.G.Cmp(key2.G) == 0)
+		add("Y", key1.Y.Cmp(key2.Y) == 0)
+
 	default:
-		return nil, fmt.Errorf("unsupported key type: %T", key)
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", key1)
 	}
+	return diff, nil
 }
 
-// ComparePublicKeys reports whether 2 public keys are equal, error if not comparable.
-func ComparePublicKeys(key1, key2 crypto.PublicKey) (bool, error) {
+// ComparePrivateKeys reports whether 2 private keys are equal, error if not comparable.
+func ComparePrivateKeys(key1, key2 crypto.PrivateKey) (bool, error) {
 	switch key1 := key1.(type) {
-	case *rsa.PublicKey:
-		key2, ok := key2.(*rsa.PublicKey)
+	case *rsa.PrivateKey:
+		other, ok := key2.(*rsa.PrivateKey)
 		if !ok {
-			return false, fmt.Errorf("key types do not match: %T and %T", key1, key2)
+			return false, fmt.Errorf("certutil: key types do not match: %T and %T", key1, key2)
 		}
-		cmp := key1.N.Cmp(key2.N) != 0 || key1.E != key2.E
-		return cmp, nil
+		cmp, err := ComparePublicKeys(&key1.PublicKey, &other.PublicKey)
+		if err != nil || !cmp {
+			return false, err
+		}
+		return key1.D.Cmp(other.D) == 0, nil
 
-	case *ecdsa.PublicKey:
-		key2, ok := key2.(*ecdsa.PublicKey)
+	case *ecdsa.PrivateKey:
+		other, ok := key2.(*ecdsa.PrivateKey)
 		if !ok {
-			return false, fmt.Errorf("key types do not match: %T and %T", key1, key2)
+			return false, fmt.Errorf("certutil: key types do not match: %T and %T", key1, key2)
 		}
-		if key1.X.Cmp(key2.X) != 0 || key1.Y.Cmp(key2.Y) != 0 {
-			return false, nil
+		cmp, err := ComparePublicKeys(&key1.PublicKey, &other.PublicKey)
+		if err != nil || !cmp {
+			return false, err
 		}
+		return key1.D.Cmp(other.D) == 0, nil
 
-		par1 := key1.Params()
-		par2 := key2.Params()
-		cmp := par1.P.Cmp(par2.P) != 0 ||
-			par1.N.Cmp(par2.N) != 0 ||
-			par1.B.Cmp(par2.B) != 0 ||
-			par1.Gx.Cmp(par2.Gx) != 0 ||
-			par1.Gy.Cmp(par2.Gy) != 0 ||
-			par1.BitSize != par2.BitSize
-		return cmp, nil
+	case ed25519.PrivateKey:
+		other, ok := key2.(ed25519.PrivateKey)
+		if !ok {
+			return false, fmt.Errorf("certutil: key types do not match: %T and %T", key1, key2)
+		}
+		return key1.Equal(other), nil
 
-	case ed25519.PublicKey:
-		key2, ok := key2.(ed25519.PublicKey)
+	case *dsa.PrivateKey:
+		other, ok := key2.(*dsa.PrivateKey)
 		if !ok {
-			return false, fmt.Errorf("key types do not match: %T and %T", key1, key2)
+			return false, fmt.Errorf("certutil: key types do not match: %T and %T", key1, key2)
 		}
-		return key1.Equal(key2), nil
+		cmp, err := ComparePublicKeys(&key1.PublicKey, &other.PublicKey)
+		if err != nil || !cmp {
+			return false, err
+		}
+		return key1.X.Cmp(other.X) == 0, nil
+
+	default:
+		return false, fmt.Errorf("certutil: unsupported key type: %T", key1)
+	}
+}
+
+// SamePEMKey reports whether 2 PEM formatted keys are the same regardless of their encoding,
+// e.g. PKCS #1 and PKCS #8 encodings of the same RSA key.
+// Private keys are compared with private keys, public keys and certificates with each other by public key.
+// Reports false if one is a private key and the other is not.
+func SamePEMKey(a, b string) (bool, error) {
+	key1, isPriv1, err := parseAnyKey(a)
+	if err != nil {
+		return false, err
+	}
+	key2, isPriv2, err := parseAnyKey(b)
+	if err != nil {
+		return false, err
+	}
 
+	switch {
+	case isPriv1 && isPriv2:
+		return ComparePrivateKeys(key1, key2)
+	case !isPriv1 && !isPriv2:
+		return ComparePublicKeys(key1, key2)
 	default:
-		return false, fmt.Errorf("unsupported key type: %T", key1)
+		return false, nil
+	}
+}
+
+// parseAnyKey parses private key, public key or certificate public key from the first PEM block.
+func parseAnyKey(s string) (key interface{}, isPrivate bool, err error) {
+	block, err := DecodePEM(s)
+	if err != nil {
+		return nil, false, err
+	}
+	if priv, err := parsePrivateKey(block.Bytes); err == nil {
+		return priv, true, nil
+	}
+	pub, err := parsePublicKey(block.Bytes)
+	if err != nil {
+		return nil, false, parseErrorf("no key found in PEM")
+	}
+	return pub, false, nil
+}
+
+// KeysMatch reports whether private key corresponds to the certificate public key.
+func KeysMatch(priv crypto.PrivateKey, cert *x509.Certificate) (bool, error) {
+	pub, err := PublicKeyFromPrivate(priv)
+	if err != nil {
+		return false, err
+	}
+	return ComparePublicKeys(pub, cert.PublicKey)
+}
+
+// AlgorithmsMatch reports whether private key and certificate public key use the same algorithm.
+// It is a cheaper check than KeysMatch: false means a wrong key type, while true and KeysMatch false
+// means the right algorithm but a different key.
+func AlgorithmsMatch(priv crypto.PrivateKey, cert *x509.Certificate) bool {
+	algo := KeyAlgorithm(priv)
+	return algo != "unknown" && algo == KeyAlgorithm(cert.PublicKey)
+}
+
+// SameKeyPair reports whether 2 keys belong to the same key pair.
+// Each argument can be a private key, a public key or a *x509.Certificate.
+func SameKeyPair(a, b interface{}) (bool, error) {
+	pub1, err := toPublicKey(a)
+	if err != nil {
+		return false, err
+	}
+	pub2, err := toPublicKey(b)
+	if err != nil {
+		return false, err
+	}
+	return ComparePublicKeys(pub1, pub2)
+}
+
+// MatchesAnyPublicKey reports whether key is equal to any key in the set, like in certificate pinning.
+// Keys of other types in the set are skipped, error is returned if keys cannot be compared.
+func MatchesAnyPublicKey(key crypto.PublicKey, set []crypto.PublicKey) (bool, error) {
+	for _, other := range set {
+		if reflect.TypeOf(key) != reflect.TypeOf(other) {
+			continue
+		}
+		ok, err := ComparePublicKeys(key, other)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PublicKeyFromPrivate returns public key of RSA, ECDSA, Ed25519 and DSA private keys.
+func PublicKeyFromPrivate(priv crypto.PrivateKey) (crypto.PublicKey, error) {
+	switch priv := priv.(type) {
+	case *rsa.PrivateKey:
+		return &priv.PublicKey, nil
+	case *ecdsa.PrivateKey:
+		return &priv.PublicKey, nil
+	case ed25519.PrivateKey:
+		return priv.Public(), nil
+	case *dsa.PrivateKey:
+		return &priv.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", priv)
 	}
 }
 
 // KeySize returns the key size in bits for a given crypto.PrivateKey or crypto.PublicKey.
+// Ed25519 keys, both private and public, are always 256 bits.
 // Returns -1 it key type is unsupported.
 func KeySize(key interface{}) int {
 	switch key := key.(type) {
@@ -123,17 +642,278 @@ func KeySize(key interface{}) int {
 	case *ecdsa.PublicKey:
 		return key.Params().BitSize
 
-	case ed25519.PrivateKey:
-		return len(key) * 8
-	case ed25519.PublicKey:
-		return len(key) * 8
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return 256
 
+	case *dsa.PrivateKey:
+		return key.P.BitLen()
+	case *dsa.PublicKey:
+		return key.P.BitLen()
 	case dsa.PrivateKey:
-		return key.Y.BitLen()
+		return key.P.BitLen()
 	case dsa.PublicKey:
-		return key.Y.BitLen()
+		return key.P.BitLen()
 
 	default:
 		return -1
 	}
 }
+
+// SecurityStrength returns estimated security strength in bits of the key according to NIST SP 800-57 Part 1,
+// e.g. 112 for RSA 2048, 128 for RSA 3072, ECDSA P-256 and Ed25519.
+// Returns 0 for keys weaker than 80 bits and -1 if key type is unsupported.
+func SecurityStrength(key interface{}) int {
+	size := KeySize(key)
+	if size < 0 {
+		return -1
+	}
+
+	switch KeyAlgorithm(key) {
+	case "RSA", "DSA":
+		switch {
+		case size >= 15360:
+			return 256
+		case size >= 7680:
+			return 192
+		case size >= 3072:
+			return 128
+		case size >= 2048:
+			return 112
+		case size >= 1024:
+			return 80
+		}
+	case "ECDSA":
+		switch {
+		case size >= 512:
+			return 256
+		case size >= 384:
+			return 192
+		case size >= 256:
+			return 128
+		case size >= 224:
+			return 112
+		case size >= 160:
+			return 80
+		}
+	case "Ed25519":
+		return 128
+	default:
+		return -1
+	}
+	return 0
+}
+
+// KeySizeFromPEM returns the key size in bits of a public key, private key or certificate
+// from a PEM formatted block.
+func KeySizeFromPEM(s string) (int, error) {
+	block, err := DecodePEM(s)
+	if err != nil {
+		return 0, err
+	}
+
+	pub, err := parsePublicKey(block.Bytes)
+	if err != nil {
+		priv, errPriv := parsePrivateKey(block.Bytes)
+		if errPriv != nil {
+			return 0, parseErrorf("no key found in PEM")
+		}
+		if pub, err = PublicKeyFromPrivate(priv); err != nil {
+			return 0, err
+		}
+	}
+	return KeySize(pub), nil
+}
+
+// KeyAlgorithm returns the algorithm name for a given crypto.PrivateKey or crypto.PublicKey.
+// Returns "unknown" if key type is unsupported.
+func KeyAlgorithm(key interface{}) string {
+	switch key := key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey, rsa.PrivateKey, rsa.PublicKey:
+		return "RSA"
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey, ecdsa.PrivateKey, ecdsa.PublicKey:
+		return "ECDSA"
+	case ed25519.PrivateKey, ed25519.PublicKey, *ed25519.PrivateKey, *ed25519.PublicKey:
+		return "Ed25519"
+	case *dsa.PrivateKey, *dsa.PublicKey, dsa.PrivateKey, dsa.PublicKey:
+		return "DSA"
+	case *PQCPublicKey:
+		return key.Algorithm // experimental, see ParsePublicKeyPQC.
+	default:
+		return "unknown"
+	}
+}
+
+// SupportedKeyTypes returns algorithm names of keys that can be parsed and compared,
+// same as returned by KeyAlgorithm.
+func SupportedKeyTypes() []string {
+	return []string{"RSA", "ECDSA", "Ed25519", "DSA"}
+}
+
+// CurveName returns the curve name like "P-256" for a given ECDSA private or public key.
+// Returns empty string for other key types.
+func CurveName(key interface{}) string {
+	var curve elliptic.Curve
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		curve = key.Curve
+	case *ecdsa.PublicKey:
+		curve = key.Curve
+	case ecdsa.PrivateKey:
+		curve = key.Curve
+	case ecdsa.PublicKey:
+		curve = key.Curve
+	}
+
+	if curve == nil {
+		return ""
+	}
+	return curve.Params().Name
+}
+
+func decryptBlock(block *pem.Block, password string) ([]byte, error) {
+	if !x509.IsEncryptedPEMBlock(block) {
+		return block.Bytes, nil
+	}
+	return x509.DecryptPEMBlock(block, []byte(password))
+}
+
+func parseRSA(der []byte) (*rsa.PrivateKey, error) {
+	key, err := x509.ParsePKCS1PrivateKey(der)
+	if err == nil {
+		return key, nil
+	}
+
+	rawKey, errPKCS8 := x509.ParsePKCS8PrivateKey(der)
+	if errPKCS8 != nil {
+		return nil, safeError("parse RSA", err)
+	}
+	key, ok := rawKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("certutil: not an RSA key: %T", rawKey)
+	}
+	return key, nil
+}
+
+func parseECDSA(der []byte) (*ecdsa.PrivateKey, error) {
+	key, err := x509.ParseECPrivateKey(der)
+	if err == nil {
+		return key, nil
+	}
+
+	rawKey, errPKCS8 := x509.ParsePKCS8PrivateKey(der)
+	if errPKCS8 != nil {
+		return nil, safeError("parse ECDSA", err)
+	}
+	key, ok := rawKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("certutil: not an ECDSA key: %T", rawKey)
+	}
+	return key, nil
+}
+
+func parseEd25519(der []byte) (ed25519.PrivateKey, error) {
+	rawKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, safeError("parse Ed25519", err)
+	}
+
+	key, ok := rawKey.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("certutil: not an Ed25519 key: %T", rawKey)
+	}
+	return key, nil
+}
+
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	rawKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+			return key, nil
+		}
+		if key, err := x509.ParseECPrivateKey(der); err == nil {
+			return key, nil
+		}
+		if key, err := parseDSA(der); err == nil {
+			return key, nil
+		}
+		return nil, parseErrorf("unknown private key format")
+	}
+
+	switch key := rawKey.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", key)
+	}
+}
+
+// parseX509Block parses "CERTIFICATE" and "TRUSTED CERTIFICATE" blocks.
+// The latter is a certificate followed by OpenSSL trust settings, only the certificate is parsed.
+func parseX509Block(block *pem.Block) (*x509.Certificate, error) {
+	der := block.Bytes
+	if block.Type == "TRUSTED CERTIFICATE" {
+		var cert asn1.RawValue
+		if _, err := asn1.Unmarshal(der, &cert); err != nil {
+			return nil, safeError("parse certificate", err)
+		}
+		der = cert.FullBytes
+	}
+	return parseX509(der)
+}
+
+func isCertificateBlock(block *pem.Block) bool {
+	return block.Type == "CERTIFICATE" || block.Type == "TRUSTED CERTIFICATE"
+}
+
+func parseX509(der []byte) (*x509.Certificate, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, safeError("parse certificate", err)
+	}
+	return cert, nil
+}
+
+func parsePublicKey(der []byte) (crypto.PublicKey, error) {
+	rawKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+			return key, nil
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, safeError("parse public key", err)
+		}
+		rawKey = cert.PublicKey
+	}
+
+	switch key := rawKey.(type) {
+	case *rsa.PublicKey:
+		return key, nil
+	case *ecdsa.PublicKey:
+		return key, nil
+	case ed25519.PublicKey:
+		return key, nil
+	case *dsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", key)
+	}
+}
+
+func toPublicKey(key interface{}) (crypto.PublicKey, error) {
+	switch key := key.(type) {
+	case *x509.Certificate:
+		return key.PublicKey, nil
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, *dsa.PrivateKey:
+		return PublicKeyFromPrivate(key)
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, *dsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", key)
+	}
+}
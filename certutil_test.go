@@ -0,0 +1,546 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComparePublicKeys(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key1    string
+		key2    string
+		want    bool
+		wantErr bool
+	}{
+		{"RSA equal", "rsa.pem", "rsa.pem", true, false},
+		{"RSA different", "rsa.pem", "rsa2.pem", false, false},
+		{"ECDSA equal", "ec256.pem", "ec256.pem", true, false},
+		{"ECDSA different", "ec256.pem", "ec256b.pem", false, false},
+		{"ECDSA different curves", "ec256.pem", "ec384.pem", false, false},
+		{"Ed25519 equal", "ed25519.pem", "ed25519.pem", true, false},
+		{"Ed25519 different", "ed25519.pem", "ed25519b.pem", false, false},
+		{"DSA equal", "dsa.pem", "dsa_pkcs8.pem", true, false},
+		{"DSA different", "dsa.pem", "dsa2_pkcs8.pem", false, false},
+		{"RSA and ECDSA", "rsa.pem", "ec256.pem", false, true},
+		{"ECDSA and Ed25519", "ec256.pem", "ed25519.pem", false, true},
+		{"Ed25519 and DSA", "ed25519.pem", "dsa.pem", false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key1 := mustPublicKey(t, tc.key1)
+			key2 := mustPublicKey(t, tc.key2)
+
+			got, err := ComparePublicKeys(key1, key2)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+
+			// comparison must be symmetric.
+			got, err = ComparePublicKeys(key2, key1)
+			if (err != nil) != tc.wantErr || got != tc.want {
+				t.Fatalf("reversed: want %v, got %v (err: %v)", tc.want, got, err)
+			}
+		})
+	}
+}
+
+func TestParseWithPassword(t *testing.T) {
+	rsaKey, err := ParseRSA(readTestdata(t, "rsa.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ParseECDSA(readTestdata(t, "ec256.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("RSA", func(t *testing.T) {
+		key, err := ParseRSAWithPassword(readTestdata(t, "rsa_enc.pem"), "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.Equal(rsaKey) {
+			t.Fatal("decrypted key does not match")
+		}
+
+		_, err = ParseRSAWithPassword(readTestdata(t, "rsa_enc.pem"), "wrong")
+		checkWrongPasswordError(t, err)
+	})
+
+	t.Run("ECDSA", func(t *testing.T) {
+		key, err := ParseECDSAWithPassword(readTestdata(t, "ec256_enc.pem"), "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.Equal(ecKey) {
+			t.Fatal("decrypted key does not match")
+		}
+
+		_, err = ParseECDSAWithPassword(readTestdata(t, "ec256_enc.pem"), "wrong")
+		checkWrongPasswordError(t, err)
+	})
+
+	t.Run("unencrypted", func(t *testing.T) {
+		key, err := ParseRSAWithPassword(readTestdata(t, "rsa.pem"), "ignored")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.Equal(rsaKey) {
+			t.Fatal("key does not match")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := ParseRSAWithPassword("not a PEM", "secret")
+		if !errors.Is(err, ErrInvalidPEM) {
+			t.Fatalf("want ErrInvalidPEM, got %v", err)
+		}
+	})
+}
+
+func TestParsePublicKeyDSA(t *testing.T) {
+	pub, err := ParsePublicKey(readTestdata(t, "dsa_pub.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pub.(*dsa.PublicKey); !ok {
+		t.Fatalf("want *dsa.PublicKey, got %T", pub)
+	}
+
+	ok, err := ComparePublicKeys(pub, mustPublicKey(t, "dsa.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("public key does not match private key")
+	}
+	if size := KeySize(pub); size != 2048 {
+		t.Fatalf("want 2048, got %d", size)
+	}
+}
+
+func TestParsePublicKeyPKCS1(t *testing.T) {
+	s := readTestdata(t, "rsa_pub_pkcs1.pem")
+	if typ, _ := PEMType(s); typ != "RSA PUBLIC KEY" {
+		t.Fatalf("want RSA PUBLIC KEY block, got %q", typ)
+	}
+
+	pub, err := ParsePublicKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		t.Fatalf("want *rsa.PublicKey, got %T", pub)
+	}
+	if !rsaPub.Equal(mustPublicKey(t, "rsa.pem")) {
+		t.Fatal("public key does not match private key")
+	}
+}
+
+func TestParseECDSAValidated(t *testing.T) {
+	key, err := ParseECDSAValidated(readTestdata(t, "ec384.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// off-curve points can arrive only with public keys.
+	offCurveY := new(big.Int).Add(key.Y, big.NewInt(1))
+
+	t.Run("JWK", func(t *testing.T) {
+		data, err := PublicKeyToJWK(&key.PublicKey, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var jwk map[string]interface{}
+		if err := json.Unmarshal(data, &jwk); err != nil {
+			t.Fatal(err)
+		}
+		jwk["y"] = base64.RawURLEncoding.EncodeToString(offCurveY.FillBytes(make([]byte, 48)))
+		data, err = json.Marshal(jwk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ParsePublicKeyJWK(data)
+		if !errors.Is(err, ErrPointNotOnCurve) {
+			t.Fatalf("want ErrPointNotOnCurve, got %v", err)
+		}
+	})
+
+	t.Run("PKIX", func(t *testing.T) {
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der[len(der)-1]++
+
+		pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		if _, err := ParsePublicKey(pemKey); err == nil {
+			t.Fatal("want error for off-curve point")
+		}
+	})
+}
+
+func TestParseECDSAWithCurve(t *testing.T) {
+	want := mustPrivateKey(t, "ec256.pem").(*ecdsa.PrivateKey)
+	other := mustPrivateKey(t, "ec256b.pem").(*ecdsa.PrivateKey)
+
+	key, err := ParseECDSAWithCurve(readTestdata(t, "ec256.pem"), elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Equal(want) {
+		t.Fatal("keys do not match")
+	}
+
+	_, err = ParseECDSAWithCurve(readTestdata(t, "ec384.pem"), elliptic.P256())
+	if err == nil || !strings.Contains(err.Error(), "unexpected curve P-384, wanted P-256") {
+		t.Fatalf("want unexpected curve error, got %v", err)
+	}
+
+	scalar := want.D.FillBytes(make([]byte, 32))
+	point := elliptic.Marshal(elliptic.P256(), want.X, want.Y)
+	otherPoint := elliptic.Marshal(elliptic.P256(), other.X, other.Y)
+
+	if _, err := ParseECDSA(strippedECKey(t, scalar, point)); err == nil {
+		t.Fatal("want ParseECDSA to fail without curve parameters")
+	}
+
+	testCases := []struct {
+		name    string
+		curve   elliptic.Curve
+		scalar  []byte
+		point   []byte
+		wantErr string
+	}{
+		{"stripped curve", elliptic.P256(), scalar, point, ""},
+		{"stripped curve and point", elliptic.P256(), scalar, nil, ""},
+		{"mismatched point", elliptic.P256(), scalar, otherPoint, "public key does not match"},
+		{"scalar too long", elliptic.P224(), scalar, nil, "invalid ECDSA private key"},
+		{"zero scalar", elliptic.P256(), make([]byte, 32), nil, "invalid ECDSA private key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseECDSAWithCurve(strippedECKey(t, tc.scalar, tc.point), tc.curve)
+			if tc.wantErr != "" {
+				if !IsParseError(err) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("want parse error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !key.Equal(want) {
+				t.Fatal("keys do not match")
+			}
+		})
+	}
+}
+
+// strippedECKey returns a SEC1 key without the named curve parameters.
+func strippedECKey(t *testing.T, scalar, point []byte) string {
+	t.Helper()
+	der, err := asn1.Marshal(struct {
+		Version    int
+		PrivateKey []byte
+		PublicKey  asn1.BitString `asn1:"optional,explicit,tag:1"`
+	}{1, scalar, asn1.BitString{Bytes: point, BitLength: 8 * len(point)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func TestKeySizeDSA(t *testing.T) {
+	priv, err := ParseDSA(readTestdata(t, "dsa.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// small Y must not affect the size, it is defined by P.
+	pub := priv.PublicKey
+	pub.Y = big.NewInt(2)
+
+	testCases := []struct {
+		name string
+		key  interface{}
+	}{
+		{"private pointer", priv},
+		{"private value", *priv},
+		{"public pointer", &pub},
+		{"public value", pub},
+	}
+
+	want := priv.P.BitLen()
+	for _, tc := range testCases {
+		if got := KeySize(tc.key); got != want {
+			t.Errorf("%s: want %d, got %d", tc.name, want, got)
+		}
+	}
+}
+
+func TestKeySizeEd25519(t *testing.T) {
+	priv, err := ParseEd25519(readTestdata(t, "ed25519.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := priv.Public().(ed25519.PublicKey)
+
+	if size := KeySize(priv); size != 256 {
+		t.Errorf("private key: want 256, got %d", size)
+	}
+	if size := KeySize(pub); size != 256 {
+		t.Errorf("public key: want 256, got %d", size)
+	}
+}
+
+func TestKeyAlgorithm(t *testing.T) {
+	rsaKey := mustPrivateKey(t, "rsa.pem").(*rsa.PrivateKey)
+	ecKey := mustPrivateKey(t, "ec256.pem").(*ecdsa.PrivateKey)
+	edKey := mustPrivateKey(t, "ed25519.pem").(ed25519.PrivateKey)
+	edPub := edKey.Public().(ed25519.PublicKey)
+	dsaKey := mustPrivateKey(t, "dsa.pem").(*dsa.PrivateKey)
+
+	testCases := []struct {
+		key  interface{}
+		want string
+	}{
+		{rsaKey, "RSA"},
+		{*rsaKey, "RSA"},
+		{&rsaKey.PublicKey, "RSA"},
+		{rsaKey.PublicKey, "RSA"},
+
+		{ecKey, "ECDSA"},
+		{*ecKey, "ECDSA"},
+		{&ecKey.PublicKey, "ECDSA"},
+		{ecKey.PublicKey, "ECDSA"},
+
+		{edKey, "Ed25519"},
+		{&edKey, "Ed25519"},
+		{edPub, "Ed25519"},
+		{&edPub, "Ed25519"},
+
+		{dsaKey, "DSA"},
+		{*dsaKey, "DSA"},
+		{&dsaKey.PublicKey, "DSA"},
+		{dsaKey.PublicKey, "DSA"},
+
+		{nil, "unknown"},
+		{"RSA", "unknown"},
+		{[]byte{1, 2, 3}, "unknown"},
+	}
+
+	seen := map[string]bool{}
+	for _, tc := range testCases {
+		got := KeyAlgorithm(tc.key)
+		if got != tc.want {
+			t.Errorf("%T: want %q, got %q", tc.key, tc.want, got)
+		}
+		if got != "unknown" {
+			seen[got] = true
+		}
+	}
+
+	supported := SupportedKeyTypes()
+	if len(supported) != len(seen) {
+		t.Fatalf("SupportedKeyTypes is out of sync with KeyAlgorithm: %v", supported)
+	}
+	for _, name := range supported {
+		if !seen[name] {
+			t.Errorf("SupportedKeyTypes has %q not returned by KeyAlgorithm", name)
+		}
+	}
+}
+
+func TestParseX509TrustedCertificate(t *testing.T) {
+	trusted := readTestdata(t, "root_trusted.pem")
+	if typ, _ := PEMType(trusted); typ != "TRUSTED CERTIFICATE" {
+		t.Fatalf("want TRUSTED CERTIFICATE block, got %q", typ)
+	}
+	want := mustCertificate(t, "root.pem")
+
+	cert, err := ParseX509(trusted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CertificatesEqual(cert, want) {
+		t.Fatal("ParseX509: certificate does not match")
+	}
+
+	cert, err = ParseX509Strict(trusted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CertificatesEqual(cert, want) {
+		t.Fatal("ParseX509Strict: certificate does not match")
+	}
+
+	chain, err := ParseX509Chain(readTestdata(t, "int.pem") + trusted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 {
+		t.Fatalf("want 2 certificates, got %d", len(chain))
+	}
+	if !CertificatesEqual(chain[1], want) {
+		t.Fatal("ParseX509Chain: certificate does not match")
+	}
+}
+
+func TestAlgorithmsMatch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		key       string
+		cert      string
+		algoMatch bool
+		keysMatch bool
+		keysErr   bool
+	}{
+		{"matching RSA", "rsa.pem", "leaf.pem", true, true, false},
+		{"matching ECDSA", "ec384.pem", "int.pem", true, true, false},
+		{"different RSA key", "rsa2.pem", "leaf.pem", true, false, false},
+		{"different ECDSA curve", "ec256.pem", "int.pem", true, false, false},
+		{"ECDSA key for RSA cert", "ec256.pem", "leaf.pem", false, false, true},
+		{"Ed25519 key for ECDSA cert", "ed25519.pem", "root.pem", false, false, true},
+		{"DSA key for RSA cert", "dsa.pem", "leaf.pem", false, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			priv := mustPrivateKey(t, tc.key)
+			cert := mustCertificate(t, tc.cert)
+
+			if got := AlgorithmsMatch(priv, cert); got != tc.algoMatch {
+				t.Fatalf("AlgorithmsMatch: want %v, got %v", tc.algoMatch, got)
+			}
+
+			got, err := KeysMatch(priv, cert)
+			if (err != nil) != tc.keysErr {
+				t.Fatalf("KeysMatch: want error %v, got %v", tc.keysErr, err)
+			}
+			if got != tc.keysMatch {
+				t.Fatalf("KeysMatch: want %v, got %v", tc.keysMatch, got)
+			}
+		})
+	}
+
+	if AlgorithmsMatch(nil, &x509.Certificate{}) {
+		t.Fatal("unknown key types must not match")
+	}
+}
+
+func TestParseKeyPair(t *testing.T) {
+	key := readTestdata(t, "rsa.pem")
+	cert := readTestdata(t, "leaf.pem")
+	chain := readTestdata(t, "chain.pem")
+	malformed := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}))
+
+	for name, s := range map[string]string{
+		"key first":  key + cert,
+		"cert first": cert + key,
+		"with chain": cert + chain + key,
+	} {
+		priv, got, err := ParseKeyPair(s)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !CertificatesEqual(got, mustCertificate(t, "leaf.pem")) {
+			t.Fatalf("%s: want leaf certificate", name)
+		}
+		if ok, _ := KeysMatch(priv, got); !ok {
+			t.Fatalf("%s: key does not match", name)
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		input      string
+		wantErr    string
+		parseError bool
+	}{
+		{"mismatched key", readTestdata(t, "rsa2.pem") + cert, "private key does not match certificate", false},
+		{"different key type", readTestdata(t, "ec256.pem") + cert, "key types do not match", false},
+		{"missing key", cert + chain, "no private key found", false},
+		{"missing cert", key, "no certificate found", false},
+		{"empty", "", "no private key and certificate found", true},
+		{"malformed key", malformed + cert, "unknown private key format", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ParseKeyPair(tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("want error %q, got %v", tc.wantErr, err)
+			}
+			if IsParseError(err) != tc.parseError {
+				t.Fatalf("want parse error %v, got %v", tc.parseError, IsParseError(err))
+			}
+		})
+	}
+}
+
+// checkWrongPasswordError allows a parse error, see ParseRSAWithPassword.
+func checkWrongPasswordError(t *testing.T, err error) {
+	t.Helper()
+	switch {
+	case err == nil:
+		t.Fatal("want error for wrong password")
+	case errors.Is(err, x509.IncorrectPasswordError), IsParseError(err):
+	default:
+		t.Fatalf("want incorrect password or parse error, got %v", err)
+	}
+}
+
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+	return string(readTestdataBytes(t, name))
+}
+
+func readTestdataBytes(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func mustPrivateKey(t *testing.T, name string) crypto.PrivateKey {
+	t.Helper()
+	priv, err := ParsePrivateKey(readTestdata(t, name))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return priv
+}
+
+func mustPublicKey(t *testing.T, name string) crypto.PublicKey {
+	t.Helper()
+	pub, err := PublicKeyFromPrivate(mustPrivateKey(t, name))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return pub
+}
@@ -0,0 +1,59 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IsRevoked reports whether certificate serial number is listed in the PEM formatted CRL.
+// CRL signature is not verified, use IsRevokedBy for that.
+// Returns an error if CRL is issued by a different issuer or is expired.
+func IsRevoked(cert *x509.Certificate, crlPEM string) (bool, error) {
+	return isRevoked(cert, nil, crlPEM)
+}
+
+// IsRevokedBy is like IsRevoked but also verifies that CRL is signed by the issuer.
+func IsRevokedBy(cert, issuer *x509.Certificate, crlPEM string) (bool, error) {
+	if issuer == nil {
+		return false, errors.New("certutil: issuer is nil")
+	}
+	return isRevoked(cert, issuer, crlPEM)
+}
+
+func isRevoked(cert, issuer *x509.Certificate, crlPEM string) (bool, error) {
+	if cert.SerialNumber == nil {
+		return false, errors.New("certutil: certificate has no serial number")
+	}
+
+	block, err := decodePEMType(crlPEM, "X509 CRL")
+	if err != nil {
+		return false, err
+	}
+
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return false, safeError("parse CRL", err)
+	}
+
+	if issuer != nil {
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			return false, fmt.Errorf("certutil: check CRL signature: %w", err)
+		}
+	}
+	if !bytes.Equal(crl.RawIssuer, cert.RawIssuer) {
+		return false, errors.New("certutil: CRL issuer does not match certificate issuer")
+	}
+	if !crl.NextUpdate.IsZero() && time.Now().After(crl.NextUpdate) {
+		return false, fmt.Errorf("certutil: CRL expired at %s", crl.NextUpdate.Format(time.RFC3339))
+	}
+
+	for _, revoked := range crl.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,101 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsRevoked(t *testing.T) {
+	leaf := mustCertificate(t, "leaf.pem")
+	issuer := mustCertificate(t, "int.pem")
+	root := mustCertificate(t, "root.pem")
+	issuerKey := mustPrivateKey(t, "ec384.pem")
+	rootKey := mustPrivateKey(t, "ec256.pem")
+
+	now := time.Now()
+	revoked := newCRL(t, issuer, issuerKey, now.Add(time.Hour), big.NewInt(100), leaf.SerialNumber)
+	notRevoked := newCRL(t, issuer, issuerKey, now.Add(time.Hour), big.NewInt(100))
+	expired := newCRL(t, issuer, issuerKey, now.Add(-time.Hour), leaf.SerialNumber)
+	otherIssuer := newCRL(t, root, rootKey, now.Add(time.Hour), leaf.SerialNumber)
+
+	testCases := []struct {
+		name      string
+		crl       string
+		want      bool
+		wantErr   string
+		wantErrBy string
+	}{
+		{"revoked", revoked, true, "", ""},
+		{"not revoked", notRevoked, false, "", ""},
+		{"expired", expired, false, "CRL expired", "CRL expired"},
+		{"wrong issuer", otherIssuer, false, "CRL issuer does not match", "check CRL signature"},
+		{"not a CRL", readTestdata(t, "leaf.pem"), false, "unexpected PEM type", "unexpected PEM type"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := IsRevoked(leaf, tc.crl)
+			checkRevoked(t, got, err, tc.want, tc.wantErr)
+
+			got, err = IsRevokedBy(leaf, issuer, tc.crl)
+			checkRevoked(t, got, err, tc.want, tc.wantErrBy)
+		})
+	}
+
+	t.Run("signed by another issuer", func(t *testing.T) {
+		got, err := IsRevokedBy(leaf, root, revoked)
+		checkRevoked(t, got, err, false, "check CRL signature")
+	})
+
+	t.Run("nil issuer", func(t *testing.T) {
+		got, err := IsRevokedBy(leaf, nil, revoked)
+		checkRevoked(t, got, err, false, "issuer is nil")
+	})
+
+	t.Run("no serial number", func(t *testing.T) {
+		cert := *leaf
+		cert.SerialNumber = nil
+		got, err := IsRevoked(&cert, revoked)
+		checkRevoked(t, got, err, false, "no serial number")
+	})
+}
+
+func checkRevoked(t *testing.T, got bool, err error, want bool, wantErr string) {
+	t.Helper()
+	switch {
+	case wantErr == "" && err != nil:
+		t.Fatal(err)
+	case wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)):
+		t.Fatalf("want error %q, got %v", wantErr, err)
+	case got != want:
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func newCRL(t *testing.T, issuer *x509.Certificate, key crypto.PrivateKey, nextUpdate time.Time, serials ...*big.Int) string {
+	t.Helper()
+	tmpl := &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: nextUpdate.Add(-24 * time.Hour),
+		NextUpdate: nextUpdate,
+	}
+	for _, serial := range serials {
+		tmpl.RevokedCertificates = append(tmpl.RevokedCertificates, pkix.RevokedCertificate{
+			SerialNumber:   serial,
+			RevocationTime: tmpl.ThisUpdate,
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, tmpl, issuer, key.(crypto.Signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+}
@@ -0,0 +1,42 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// CSRFromCertificate returns PEM formatted certificate signing request with subject and SANs
+// (DNS names, IP addresses, emails and URIs) copied from the certificate, signed by RSA, ECDSA or Ed25519 key.
+// Key may differ from the certificate key, e.g. when it is rotated during renewal.
+func CSRFromCertificate(cert *x509.Certificate, priv crypto.PrivateKey) ([]byte, error) {
+	switch priv.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", priv)
+	}
+
+	tpl := &x509.CertificateRequest{
+		RawSubject:     cert.RawSubject, // preserves attribute order and encoding.
+		DNSNames:       cert.DNSNames,
+		IPAddresses:    cert.IPAddresses,
+		EmailAddresses: cert.EmailAddresses,
+		URIs:           cert.URIs,
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, tpl, priv)
+	if err != nil {
+		return nil, fmt.Errorf("certutil: create CSR: %w", err)
+	}
+
+	block := &pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: der,
+	}
+	return pem.EncodeToMemory(block), nil
+}
@@ -0,0 +1,80 @@
+package certutil
+
+import (
+	"crypto/dsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+var oidPublicKeyDSA = asn1.ObjectIdentifier{1, 2, 840, 10040, 4, 1}
+
+// dsaPrivateKey is OpenSSL traditional ("DSA PRIVATE KEY") encoding.
+type dsaPrivateKey struct {
+	Version int
+	P, Q, G *big.Int
+	Y, X    *big.Int
+}
+
+type dsaParameters struct {
+	P, Q, G *big.Int
+}
+
+// parseDSA tries OpenSSL traditional encoding first, then PKCS #8.
+func parseDSA(der []byte) (*dsa.PrivateKey, error) {
+	var key dsaPrivateKey
+	if err := unmarshalDER(der, &key); err == nil {
+		if key.Version != 0 {
+			return nil, parseErrorf("unsupported DSA key version: %d", key.Version)
+		}
+		return newDSAPrivateKey(key.P, key.Q, key.G, key.Y, key.X)
+	}
+
+	var pkcs8 struct {
+		Version    int
+		Algorithm  pkix.AlgorithmIdentifier
+		PrivateKey []byte
+	}
+	if err := unmarshalDER(der, &pkcs8); err != nil {
+		return nil, safeError("parse DSA", err)
+	}
+	if !pkcs8.Algorithm.Algorithm.Equal(oidPublicKeyDSA) {
+		return nil, fmt.Errorf("certutil: not a DSA key: %s", pkcs8.Algorithm.Algorithm)
+	}
+
+	var params dsaParameters
+	if err := unmarshalDER(pkcs8.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, safeError("parse DSA parameters", err)
+	}
+	x := new(big.Int)
+	if err := unmarshalDER(pkcs8.PrivateKey, &x); err != nil {
+		return nil, safeError("parse DSA", err)
+	}
+	if params.P == nil || params.P.Sign() <= 0 {
+		return nil, parseErrorf("invalid DSA key")
+	}
+	y := new(big.Int).Exp(params.G, x, params.P)
+	return newDSAPrivateKey(params.P, params.Q, params.G, y, x)
+}
+
+// newDSAPrivateKey checks that all values are positive and public value matches the private.
+func newDSAPrivateKey(p, q, g, y, x *big.Int) (*dsa.PrivateKey, error) {
+	for _, v := range []*big.Int{p, q, g, y, x} {
+		if v == nil || v.Sign() <= 0 {
+			return nil, parseErrorf("invalid DSA key")
+		}
+	}
+	if new(big.Int).Exp(g, x, p).Cmp(y) != 0 {
+		return nil, parseErrorf("invalid DSA key")
+	}
+
+	key := &dsa.PrivateKey{
+		PublicKey: dsa.PublicKey{
+			Parameters: dsa.Parameters{P: p, Q: q, G: g},
+			Y:          y,
+		},
+		X: x,
+	}
+	return key, nil
+}
@@ -0,0 +1,47 @@
+package certutil
+
+import (
+	"crypto/dsa"
+	"testing"
+)
+
+func TestParseDSA(t *testing.T) {
+	trad, err := ParseDSA(readTestdata(t, "dsa.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := ParseDSA(readTestdata(t, "dsa_pkcs8.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trad.X.Cmp(pkcs8.X) != 0 {
+		t.Fatal("private keys do not match")
+	}
+	if ok, _ := ComparePublicKeys(&trad.PublicKey, &pkcs8.PublicKey); !ok {
+		t.Fatal("public keys do not match")
+	}
+
+	for _, name := range []string{"dsa.pem", "dsa_pkcs8.pem"} {
+		priv, err := ParsePrivateKey(readTestdata(t, name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, ok := priv.(*dsa.PrivateKey); !ok {
+			t.Fatalf("%s: want *dsa.PrivateKey, got %T", name, priv)
+		}
+	}
+}
+
+func TestParseDSAInvalid(t *testing.T) {
+	// X is changed, so g^x mod p does not match Y.
+	_, err := ParseDSA(readTestdata(t, "dsa_corrupt.pem"))
+	if err == nil || err.Error() != "certutil: invalid DSA key" {
+		t.Fatalf("want invalid DSA key error, got %v", err)
+	}
+	if _, err := ParseDSA(readTestdata(t, "rsa.pem")); err == nil {
+		t.Fatal("want error for RSA key")
+	}
+	if _, err := ParseDSA(readTestdata(t, "ed25519.pem")); err == nil {
+		t.Fatal("want error for Ed25519 key")
+	}
+}
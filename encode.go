@@ -0,0 +1,107 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// EncodePrivateKeyPEM encodes RSA, ECDSA and Ed25519 private keys to a PEM formatted block.
+// RSA keys are encoded as PKCS #1, ECDSA as SEC 1 and Ed25519 as PKCS #8.
+func EncodePrivateKeyPEM(key crypto.PrivateKey) (string, error) {
+	var block *pem.Block
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		block = &pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}
+
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: der,
+		}
+
+	case ed25519.PrivateKey:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: der,
+		}
+
+	default:
+		return "", fmt.Errorf("certutil: unsupported key type: %T", key)
+	}
+	return EncodePEM(block), nil
+}
+
+// EncodePKCS8PEM encodes RSA, ECDSA and Ed25519 private keys to a PKCS #8 "PRIVATE KEY" PEM formatted block.
+// Unlike EncodePrivateKeyPEM the encoding is the same for all key types.
+func EncodePKCS8PEM(priv crypto.PrivateKey) (string, error) {
+	switch priv.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return "", fmt.Errorf("certutil: unsupported key type: %T", priv)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return "", err
+	}
+	block := &pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}
+	return EncodePEM(block), nil
+}
+
+// EncodeCertificatePEM encodes certificate to a PEM formatted block.
+func EncodeCertificatePEM(cert *x509.Certificate) string {
+	block := &pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	}
+	return EncodePEM(block)
+}
+
+// EncodePublicKeyPEM encodes public key to a PKIX "PUBLIC KEY" PEM formatted block.
+func EncodePublicKeyPEM(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+
+	block := &pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	}
+	return EncodePEM(block), nil
+}
+
+// PublicKeyPEMFromPrivatePEM returns PEM formatted public key of the PEM formatted private key.
+// See ParsePrivateKey for the supported formats.
+func PublicKeyPEMFromPrivatePEM(s string) (string, error) {
+	priv, err := ParsePrivateKey(s)
+	if err != nil {
+		return "", err
+	}
+
+	pub, err := PublicKeyFromPrivate(priv)
+	if err != nil {
+		return "", err
+	}
+	return EncodePublicKeyPEM(pub)
+}
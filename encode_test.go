@@ -0,0 +1,37 @@
+package certutil
+
+import "testing"
+
+func TestEncodePKCS8PEM(t *testing.T) {
+	for _, name := range []string{"rsa.pem", "ec256.pem", "ec384.pem", "ed25519.pem"} {
+		t.Run(name, func(t *testing.T) {
+			priv := mustPrivateKey(t, name)
+
+			s, err := EncodePKCS8PEM(priv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typ, _ := PEMType(s); typ != "PRIVATE KEY" {
+				t.Fatalf("want PRIVATE KEY block, got %q", typ)
+			}
+
+			got, err := ParsePrivateKey(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := ComparePrivateKeys(priv, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Fatal("round-trip key does not match")
+			}
+		})
+	}
+}
+
+func TestEncodePKCS8PEMUnsupported(t *testing.T) {
+	if _, err := EncodePKCS8PEM(mustPrivateKey(t, "dsa.pem")); err == nil {
+		t.Fatal("want error for DSA key")
+	}
+}
@@ -0,0 +1,52 @@
+package certutil
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// IsParseError reports whether err is a failure to decode or parse PEM, DER or key data.
+func IsParseError(err error) bool {
+	var pe *parseError
+	return errors.As(err, &pe) || errors.Is(err, ErrInvalidPEM)
+}
+
+// parseError wraps an error from decoding or parsing key material.
+// Error message never contains raw input bytes, see safeError.
+type parseError struct {
+	op  string
+	err error
+}
+
+func (e *parseError) Error() string {
+	if e.op == "" {
+		return "certutil: " + scrubBytes(e.err.Error())
+	}
+	return "certutil: " + e.op + ": " + scrubBytes(e.err.Error())
+}
+
+func (e *parseError) Unwrap() error { return e.err }
+
+// safeError wraps err from the op like "parse RSA".
+// Hex, base64 and byte slice fragments that might come from the input are redacted
+// from the message, so the error is safe to log. The original error is still available via errors.As.
+func safeError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &parseError{op: op, err: err}
+}
+
+// parseErrorf is like fmt.Errorf for malformed input without an underlying error.
+// Format must not have "certutil: " prefix, it is added by parseError.
+func parseErrorf(format string, args ...interface{}) error {
+	return &parseError{err: fmt.Errorf(format, args...)}
+}
+
+// rawBytesRe matches long hex and base64 runs and formatted byte slices like "[48 130 1 10 ...]".
+var rawBytesRe = regexp.MustCompile(`[0-9A-Za-z+/]{32,}={0,2}|(?:[0-9A-Fa-f]{2}[: ]){15,}[0-9A-Fa-f]{2}|\[(?:\d{1,3} ){7,}\d{1,3}\]`)
+
+func scrubBytes(s string) string {
+	return rawBytesRe.ReplaceAllString(s, "[redacted]")
+}
@@ -0,0 +1,110 @@
+package certutil
+
+import (
+	"crypto/elliptic"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsParseError(t *testing.T) {
+	garbage := func(typ string) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: []byte("garbage data")}))
+	}
+	certPEM := readTestdata(t, "root.pem")
+	keyPEM := readTestdata(t, "rsa.pem")
+
+	ignore2 := func(_ interface{}, err error) error { return err }
+
+	testCases := []struct {
+		name  string
+		parse func(s string) error
+		types []string // block types with garbage content.
+		wrong string   // valid block of a wrong type.
+	}{
+		{"ParseRSA", func(s string) error { return ignore2(ParseRSA(s)) }, []string{"RSA PRIVATE KEY", "PRIVATE KEY"}, certPEM},
+		{"ParseRSAValidated", func(s string) error { return ignore2(ParseRSAValidated(s)) }, []string{"RSA PRIVATE KEY"}, certPEM},
+		{"ParseRSAWithPassword", func(s string) error { return ignore2(ParseRSAWithPassword(s, "secret")) }, []string{"RSA PRIVATE KEY"}, certPEM},
+		{"ParseRSAStrict", func(s string) error { return ignore2(ParseRSAStrict(s)) }, []string{"RSA PRIVATE KEY"}, certPEM},
+		{"ParseECDSA", func(s string) error { return ignore2(ParseECDSA(s)) }, []string{"EC PRIVATE KEY", "PRIVATE KEY"}, certPEM},
+		{"ParseECDSAOnCurve", func(s string) error { return ignore2(ParseECDSAOnCurve(s)) }, []string{"EC PRIVATE KEY"}, certPEM},
+		{"ParseECDSAWithCurve", func(s string) error { return ignore2(ParseECDSAWithCurve(s, elliptic.P256())) }, []string{"EC PRIVATE KEY"}, certPEM},
+		{"ParseECDSAValidated", func(s string) error { return ignore2(ParseECDSAValidated(s)) }, []string{"EC PRIVATE KEY"}, certPEM},
+		{"ParseECDSAWithPassword", func(s string) error { return ignore2(ParseECDSAWithPassword(s, "secret")) }, []string{"EC PRIVATE KEY"}, certPEM},
+		{"ParseECDSAStrict", func(s string) error { return ignore2(ParseECDSAStrict(s)) }, []string{"EC PRIVATE KEY"}, certPEM},
+		{"ParseEd25519", func(s string) error { return ignore2(ParseEd25519(s)) }, []string{"PRIVATE KEY"}, certPEM},
+		{"ParseEd25519Strict", func(s string) error { return ignore2(ParseEd25519Strict(s)) }, []string{"PRIVATE KEY"}, certPEM},
+		{"ParseDSA", func(s string) error { return ignore2(ParseDSA(s)) }, []string{"DSA PRIVATE KEY", "PRIVATE KEY"}, certPEM},
+		{"ParsePrivateKey", func(s string) error { return ignore2(ParsePrivateKey(s)) }, []string{"PRIVATE KEY", "RSA PRIVATE KEY"}, certPEM},
+		{"ParsePrivateKeyStrict", func(s string) error { return ignore2(ParsePrivateKeyStrict(s)) }, []string{"PRIVATE KEY", "DSA PRIVATE KEY"}, certPEM},
+		{"ParseX509", func(s string) error { return ignore2(ParseX509(s)) }, []string{"CERTIFICATE", "TRUSTED CERTIFICATE"}, keyPEM},
+		{"ParseX509Strict", func(s string) error { return ignore2(ParseX509Strict(s)) }, []string{"CERTIFICATE"}, keyPEM},
+		{"ParseX509Chain", func(s string) error { return ignore2(ParseX509Chain(s)) }, []string{"CERTIFICATE"}, keyPEM},
+		{"ParseX509ChainCached", func(s string) error { return ignore2(ParseX509ChainCached(s)) }, []string{"CERTIFICATE"}, keyPEM},
+		{"ParseCSR", func(s string) error { return ignore2(ParseCSR(s)) }, []string{"CERTIFICATE REQUEST"}, certPEM},
+		{"ParsePublicKey", func(s string) error { return ignore2(ParsePublicKey(s)) }, []string{"PUBLIC KEY", "RSA PUBLIC KEY"}, ""},
+		{"ParsePublicKeyStrict", func(s string) error { return ignore2(ParsePublicKeyStrict(s)) }, []string{"PUBLIC KEY"}, certPEM},
+		{"ParseRSAPublicKey", func(s string) error { return ignore2(ParseRSAPublicKey(s)) }, []string{"PUBLIC KEY"}, ""},
+		{"ParseECDSAPublicKey", func(s string) error { return ignore2(ParseECDSAPublicKey(s)) }, []string{"PUBLIC KEY"}, ""},
+		{"ParsePublicKeyWithPolicy", func(s string) error { return ignore2(ParsePublicKeyWithPolicy(s)) }, []string{"PUBLIC KEY"}, ""},
+		{"ParsePublicKeyPQC", func(s string) error { return ignore2(ParsePublicKeyPQC(s)) }, []string{"PUBLIC KEY"}, ""},
+		{"ParseKeyPair", func(s string) error { _, _, err := ParseKeyPair(s); return err }, []string{"PRIVATE KEY", "CERTIFICATE"}, ""},
+		{"KeySizeFromPEM", func(s string) error { return ignore2(KeySizeFromPEM(s)) }, []string{"PUBLIC KEY", "PRIVATE KEY"}, ""},
+		{"SamePEMKey", func(s string) error { return ignore2(SamePEMKey(s, keyPEM)) }, []string{"PUBLIC KEY", "PRIVATE KEY"}, ""},
+		{"ParsePrivateKeyFromReader", func(s string) error { return ignore2(ParsePrivateKeyFromReader(strings.NewReader(s))) }, []string{"PRIVATE KEY"}, ""},
+		{"ParsePublicKeyFromReader", func(s string) error { return ignore2(ParsePublicKeyFromReader(strings.NewReader(s))) }, []string{"PUBLIC KEY"}, ""},
+		{"ParseX509ChainFromReader", func(s string) error { return ignore2(ParseX509ChainFromReader(strings.NewReader(s))) }, []string{"CERTIFICATE"}, keyPEM},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inputs := []string{"", "not a PEM"}
+			for _, typ := range tc.types {
+				inputs = append(inputs, garbage(typ))
+			}
+			if tc.wrong != "" {
+				inputs = append(inputs, tc.wrong)
+			}
+
+			for _, input := range inputs {
+				err := tc.parse(input)
+				if !IsParseError(err) {
+					t.Errorf("input %.40q: want parse error, got %v", input, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsParseErrorNonPEM(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{"ParseX509FromDER", ignoreValue(ParseX509FromDER([]byte("garbage")))},
+		{"ParseX509FromBase64 invalid base64", ignoreValue(ParseX509FromBase64("!!!"))},
+		{"ParseX509FromBase64 garbage", ignoreValue(ParseX509FromBase64("Z2FyYmFnZQ=="))},
+		{"ParsePublicKeyJWK invalid JSON", ignoreValue(ParsePublicKeyJWK([]byte("{")))},
+		{"ParsePublicKeyJWK missing member", ignoreValue(ParsePublicKeyJWK([]byte(`{"kty":"RSA","e":"AQAB"}`)))},
+		{"ParsePublicKeyFromSSH no key", ignoreValue(ParsePublicKeyFromSSH("garbage"))},
+		{"ParsePublicKeyFromSSH truncated", ignoreValue(ParsePublicKeyFromSSH("ssh-rsa AAAA"))},
+		{"ParsePKCS12", func() error { _, _, _, err := ParsePKCS12([]byte("garbage"), ""); return err }()},
+		{"NextCertificate", func() error {
+			_, _, err := NextCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}))
+			return err
+		}()},
+	}
+
+	for _, tc := range testCases {
+		if !IsParseError(tc.err) {
+			t.Errorf("%s: want parse error, got %v", tc.name, tc.err)
+		}
+	}
+
+	if IsParseError(nil) || IsParseError(errors.New("other")) {
+		t.Fatal("unexpected parse error")
+	}
+}
+
+func ignoreValue(_ interface{}, err error) error { return err }
@@ -0,0 +1,202 @@
+package certutil
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseRSAFromFile private key from a PEM formatted file.
+func ParseRSAFromFile(path string) (*rsa.PrivateKey, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRSA(s)
+}
+
+// ParseECDSAFromFile private key from a PEM formatted file.
+func ParseECDSAFromFile(path string) (*ecdsa.PrivateKey, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseECDSA(s)
+}
+
+// ParseEd25519FromFile private key from a PEM formatted file.
+func ParseEd25519FromFile(path string) (ed25519.PrivateKey, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEd25519(s)
+}
+
+// ParsePrivateKeyFromFile private key from a PEM formatted file.
+// See ParsePrivateKey for the supported formats.
+func ParsePrivateKeyFromFile(path string) (crypto.PrivateKey, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKey(s)
+}
+
+// ParseX509FromFile certificate from a PEM formatted file.
+func ParseX509FromFile(path string) (*x509.Certificate, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseX509(s)
+}
+
+// ParseX509ChainFromFile certificates from a PEM formatted file.
+func ParseX509ChainFromFile(path string) ([]*x509.Certificate, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseX509Chain(s)
+}
+
+// ParsePublicKeyFromFile public key from a PEM formatted file.
+func ParsePublicKeyFromFile(path string) (crypto.PublicKey, error) {
+	s, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(s)
+}
+
+// ParseX509ChainFromReader certificates from PEM formatted data.
+func ParseX509ChainFromReader(r io.Reader) ([]*x509.Certificate, error) {
+	s, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseX509Chain(s)
+}
+
+// ParsePrivateKeyFromReader private key from PEM formatted data.
+// See ParsePrivateKey for the supported formats.
+func ParsePrivateKeyFromReader(r io.Reader) (crypto.PrivateKey, error) {
+	s, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKey(s)
+}
+
+// ParsePublicKeyFromReader public key from PEM formatted data.
+func ParsePublicKeyFromReader(r io.Reader) (crypto.PublicKey, error) {
+	s, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(s)
+}
+
+// ParseDir certificates from all ".pem", ".crt" and ".cer" files in dir and its subdirectories.
+// Files with PEM blocks but without certificates, like private keys, are skipped.
+// Files that cannot be parsed do not stop the scan, their errors are returned as *DirError
+// together with certificates from other files.
+// Context is checked between files, on cancellation ctx.Err() is returned.
+func ParseDir(ctx context.Context, dir string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	var dirErr DirError
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err != nil {
+			dirErr.Errors = append(dirErr.Errors, &FileError{Path: path, Err: err})
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".pem", ".crt", ".cer":
+		default:
+			return nil
+		}
+
+		s, err := readFile(path)
+		if err != nil {
+			dirErr.Errors = append(dirErr.Errors, &FileError{Path: path, Err: err})
+			return nil
+		}
+		// keys and other blocks without certificates are not errors.
+		if counts := CountPEMBlocks(s); len(counts) > 0 && counts["CERTIFICATE"]+counts["TRUSTED CERTIFICATE"] == 0 {
+			return nil
+		}
+
+		chain, err := ParseX509Chain(s)
+		if err != nil {
+			dirErr.Errors = append(dirErr.Errors, &FileError{Path: path, Err: err})
+			return nil
+		}
+		certs = append(certs, chain...)
+		return nil
+	})
+	if err != nil {
+		return certs, err
+	}
+	if len(dirErr.Errors) > 0 {
+		return certs, &dirErr
+	}
+	return certs, nil
+}
+
+// DirError is returned by ParseDir when some files cannot be parsed.
+type DirError struct {
+	Errors []*FileError
+}
+
+func (e *DirError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("certutil: %d files failed, first: %v", len(e.Errors), e.Errors[0])
+}
+
+// FileError is an error for a single file.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("certutil: file %q: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error { return e.Err }
+
+func readFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("certutil: read %q: %w", path, err)
+	}
+	return string(b), nil
+}
+
+func readAll(r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("certutil: read: %w", err)
+	}
+	return string(b), nil
+}
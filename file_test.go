@@ -0,0 +1,83 @@
+package certutil
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"leaf.pem":        readTestdata(t, "leaf.pem"),
+		"chain.crt":       readTestdata(t, "chain.pem"),
+		"leaf.key.pem":    readTestdata(t, "rsa.pem"),
+		"pub.pem":         readTestdata(t, "dsa_pub.pem"),
+		"notes.txt":       "not a certificate",
+		"bad.pem":         "not a PEM",
+		"sub/broken.cer":  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})),
+		"sub/trusted.PEM": readTestdata(t, "root_trusted.pem"),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	certs, err := ParseDir(context.Background(), dir)
+	if len(certs) != 4 {
+		t.Fatalf("want 4 certificates, got %d", len(certs))
+	}
+
+	var dirErr *DirError
+	if !errors.As(err, &dirErr) {
+		t.Fatalf("want *DirError, got %v", err)
+	}
+	var paths []string
+	for _, fileErr := range dirErr.Errors {
+		rel, _ := filepath.Rel(dir, fileErr.Path)
+		paths = append(paths, filepath.ToSlash(rel))
+		if !IsParseError(fileErr) {
+			t.Errorf("%s: want parse error, got %v", rel, fileErr.Err)
+		}
+	}
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != "bad.pem" || paths[1] != "sub/broken.cer" {
+		t.Fatalf("want errors for bad.pem and sub/broken.cer, got %v", paths)
+	}
+}
+
+func TestParseDirOnlyCerts(t *testing.T) {
+	dir := t.TempDir()
+	for name, fixture := range map[string]string{"leaf.pem": "leaf.pem", "leaf.key": "rsa.pem", "key.pem": "rsa.pem"} {
+		if err := os.WriteFile(filepath.Join(dir, name), readTestdataBytes(t, fixture), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	certs, err := ParseDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 {
+		t.Fatalf("want 1 certificate, got %d", len(certs))
+	}
+}
+
+func TestParseDirCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseDir(ctx, "testdata")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package certutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+)
+
+// GenerateRSA private key of the given size in bits. Sizes below 2048 are rejected.
+func GenerateRSA(bits int) (*rsa.PrivateKey, error) {
+	if bits < 2048 {
+		return nil, fmt.Errorf("certutil: RSA key size is too small: %d, must be at least 2048", bits)
+	}
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// GenerateECDSA private key on the given curve.
+func GenerateECDSA(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(curve, rand.Reader)
+}
+
+// GenerateEd25519 private key.
+func GenerateEd25519() (ed25519.PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	return priv, err
+}
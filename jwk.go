@@ -0,0 +1,219 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// jwk is JSON Web Key from RFC 7517, only public key members are used.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// ParsePublicKeyJWK RSA, ECDSA (P-256, P-384, P-521) and Ed25519 public keys from a JSON Web Key.
+// Private key members are ignored.
+func ParsePublicKeyJWK(jsonBytes []byte) (crypto.PublicKey, error) {
+	var key jwk
+	if err := json.Unmarshal(jsonBytes, &key); err != nil {
+		return nil, safeError("parse JWK", err)
+	}
+
+	switch key.Kty {
+	case "RSA":
+		n, err := jwkBigInt(key.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := jwkBigInt(key.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, parseErrorf("invalid RSA exponent in JWK")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		curve, ok := jwkCurves[key.Crv]
+		if !ok {
+			return nil, fmt.Errorf("certutil: unsupported JWK curve: %q", key.Crv)
+		}
+		size := curveByteLen(curve)
+		x, err := jwkBytes(key.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := jwkBytes(key.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != size || len(y) != size {
+			return nil, parseErrorf("invalid EC coordinate length in JWK")
+		}
+
+		pub := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrPointNotOnCurve
+		}
+		return pub, nil
+
+	case "OKP":
+		if key.Crv != "Ed25519" {
+			return nil, fmt.Errorf("certutil: unsupported JWK curve: %q", key.Crv)
+		}
+		x, err := jwkBytes(key.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, parseErrorf("invalid Ed25519 key length in JWK")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("certutil: unsupported JWK key type: %q", key.Kty)
+	}
+}
+
+// PublicKeyToJWK returns JSON Web Key for RSA, ECDSA (P-256, P-384, P-521) and Ed25519 public key
+// with "use" set to "sig". When kid is empty, the RFC 7638 SHA-256 thumbprint of the key is used.
+func PublicKeyToJWK(pub crypto.PublicKey, kid string) ([]byte, error) {
+	key, err := newJWK(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	if kid == "" {
+		if kid, err = key.thumbprint(); err != nil {
+			return nil, err
+		}
+	}
+	key.Kid = kid
+	key.Use = "sig"
+	return json.Marshal(key)
+}
+
+func newJWK(pub crypto.PublicKey) (*jwk, error) {
+	enc := base64.RawURLEncoding
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		key := &jwk{
+			Kty: "RSA",
+			N:   enc.EncodeToString(pub.N.Bytes()),
+			E:   enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+		return key, nil
+
+	case *ecdsa.PublicKey:
+		name := CurveName(pub)
+		if _, ok := jwkCurves[name]; !ok {
+			return nil, fmt.Errorf("certutil: unsupported JWK curve: %q", name)
+		}
+		size := curveByteLen(pub.Curve)
+		key := &jwk{
+			Kty: "EC",
+			Crv: name,
+			X:   enc.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+			Y:   enc.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+		}
+		return key, nil
+
+	case ed25519.PublicKey:
+		key := &jwk{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   enc.EncodeToString(pub),
+		}
+		return key, nil
+
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", pub)
+	}
+}
+
+// thumbprint returns RFC 7638 thumbprint: SHA-256 of required members in lexicographic order.
+func (k *jwk) thumbprint() (string, error) {
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	default:
+		return "", fmt.Errorf("certutil: unsupported JWK key type: %q", k.Kty)
+	}
+
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+var jwkCurves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// jwkBytes decodes base64url value, padding is tolerated.
+func jwkBytes(s string) ([]byte, error) {
+	if s == "" {
+		return nil, parseErrorf("missing JWK member")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, safeError("decode JWK", err)
+	}
+	return b, nil
+}
+
+func jwkBigInt(s string) (*big.Int, error) {
+	b, err := jwkBytes(s)
+	if err != nil {
+		return nil, err
+	}
+	n := new(big.Int).SetBytes(b)
+	if n.Sign() == 0 {
+		return nil, parseErrorf("invalid JWK integer")
+	}
+	return n, nil
+}
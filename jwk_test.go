@@ -0,0 +1,255 @@
+package certutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// RFC 7638, section 3.1.
+const rfc7638Key = `{
+	"kty": "RSA",
+	"n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	"e": "AQAB",
+	"alg": "RS256",
+	"kid": "2011-04-29"
+}`
+
+func TestParsePublicKeyJWK(t *testing.T) {
+	testCases := []struct {
+		name  string
+		json  string
+		algo  string
+		size  int
+		curve string
+	}{
+		{"RFC 7638 RSA", rfc7638Key, "RSA", 2048, ""},
+		{
+			// RFC 7517, appendix A.1.
+			"RFC 7517 EC",
+			`{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"}`,
+			"ECDSA", 256, "P-256",
+		},
+		{
+			// RFC 8037, appendix A.2.
+			"RFC 8037 Ed25519",
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			"Ed25519", 256, "",
+		},
+		{
+			"padded Ed25519",
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo="}`,
+			"Ed25519", 256, "",
+		},
+		{
+			"private members ignored",
+			`{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			"Ed25519", 256, "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pub, err := ParsePublicKeyJWK([]byte(tc.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := KeyAlgorithm(pub); got != tc.algo {
+				t.Fatalf("want %s, got %s", tc.algo, got)
+			}
+			if got := KeySize(pub); got != tc.size {
+				t.Fatalf("want %d, got %d", tc.size, got)
+			}
+			if got := CurveName(pub); got != tc.curve {
+				t.Fatalf("want %q, got %q", tc.curve, got)
+			}
+
+			switch pub := pub.(type) {
+			case *rsa.PublicKey:
+				if pub.E != 65537 {
+					t.Fatalf("want exponent 65537, got %d", pub.E)
+				}
+			case *ecdsa.PublicKey, ed25519.PublicKey:
+			default:
+				t.Fatalf("unexpected key type %T", pub)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyJWKInvalid(t *testing.T) {
+	const (
+		x256 = "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"
+		y256 = "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"
+	)
+
+	testCases := []struct {
+		name       string
+		json       string
+		wantErr    string
+		parseError bool
+	}{
+		{"not JSON", `{"kty":`, "parse JWK", true},
+		{"unsupported kty", `{"kty":"oct","k":"AQAB"}`, "unsupported JWK key type", false},
+		{"missing kty", `{"n":"AQAB","e":"AQAB"}`, "unsupported JWK key type", false},
+		{"missing n", `{"kty":"RSA","e":"AQAB"}`, "missing JWK member", true},
+		{"zero n", `{"kty":"RSA","n":"AA","e":"AQAB"}`, "invalid JWK integer", true},
+		{"exponent 1", `{"kty":"RSA","n":"0vx7","e":"AQ"}`, "invalid RSA exponent", true},
+		{"huge exponent", `{"kty":"RSA","n":"0vx7","e":"AQAAAAAB"}`, "invalid RSA exponent", true},
+		{"bad base64", `{"kty":"RSA","n":"0vx7!","e":"AQAB"}`, "decode JWK", true},
+		{"unsupported EC curve", `{"kty":"EC","crv":"P-192","x":"` + x256 + `","y":"` + y256 + `"}`, "unsupported JWK curve", false},
+		{"missing y", `{"kty":"EC","crv":"P-256","x":"` + x256 + `"}`, "missing JWK member", true},
+		{"short x", `{"kty":"EC","crv":"P-256","x":"AQAB","y":"` + y256 + `"}`, "invalid EC coordinate length", true},
+		{"wrong curve size", `{"kty":"EC","crv":"P-384","x":"` + x256 + `","y":"` + y256 + `"}`, "invalid EC coordinate length", true},
+		{"unsupported OKP curve", `{"kty":"OKP","crv":"X25519","x":"` + x256 + `"}`, "unsupported JWK curve", false},
+		{"short Ed25519", `{"kty":"OKP","crv":"Ed25519","x":"AQAB"}`, "invalid Ed25519 key length", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePublicKeyJWK([]byte(tc.json))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("want error %q, got %v", tc.wantErr, err)
+			}
+			if got := IsParseError(err); got != tc.parseError {
+				t.Fatalf("want IsParseError %v, got %v for %v", tc.parseError, got, err)
+			}
+		})
+	}
+
+	offCurve := `{"kty":"EC","crv":"P-256","x":"` + x256 + `","y":"` + x256 + `"}`
+	if _, err := ParsePublicKeyJWK([]byte(offCurve)); !errors.Is(err, ErrPointNotOnCurve) {
+		t.Fatalf("want ErrPointNotOnCurve, got %v", err)
+	}
+}
+
+func TestPublicKeyToJWK(t *testing.T) {
+	p521, err := ParsePublicKeyFromSSH(readTestdata(t, "ssh_ecdsa_521.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		pub  interface{}
+		kty  string
+		crv  string
+	}{
+		{"RSA", mustPublicKey(t, "rsa.pem"), "RSA", ""},
+		{"P-256", mustPublicKey(t, "ec256.pem"), "EC", "P-256"},
+		{"P-384", mustPublicKey(t, "ec384.pem"), "EC", "P-384"},
+		{"P-521", p521, "EC", "P-521"},
+		{"Ed25519", mustPublicKey(t, "ed25519.pem"), "OKP", "Ed25519"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := PublicKeyToJWK(tc.pub, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var members map[string]string
+			if err := json.Unmarshal(data, &members); err != nil {
+				t.Fatal(err)
+			}
+			if members["kty"] != tc.kty || members["crv"] != tc.crv || members["use"] != "sig" {
+				t.Fatalf("unexpected members: %s", data)
+			}
+
+			got, err := ParsePublicKeyJWK(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, _ := ComparePublicKeys(tc.pub, got); !ok {
+				t.Fatal("keys do not match")
+			}
+
+			// kid is the thumbprint, so it is stable for the same key.
+			again, err := PublicKeyToJWK(got, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(data) {
+				t.Fatalf("want %s, got %s", data, again)
+			}
+
+			data, err = PublicKeyToJWK(tc.pub, "my-key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `"kid":"my-key"`) {
+				t.Fatalf("want kid my-key, got %s", data)
+			}
+		})
+	}
+}
+
+func TestPublicKeyToJWKThumbprint(t *testing.T) {
+	testCases := []struct {
+		name string
+		json string
+		want string
+	}{
+		// RFC 7638, section 3.1.
+		{"RSA", rfc7638Key, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"},
+		// RFC 8037, appendix A.3.
+		{
+			"Ed25519", `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			"kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pub, err := ParsePublicKeyJWK([]byte(tc.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := PublicKeyToJWK(pub, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var key jwk
+			if err := json.Unmarshal(data, &key); err != nil {
+				t.Fatal(err)
+			}
+			if key.Kid != tc.want {
+				t.Fatalf("want kid %s, got %s", tc.want, key.Kid)
+			}
+		})
+	}
+}
+
+func TestPublicKeyToJWKUnsupported(t *testing.T) {
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		pub     interface{}
+		wantErr string
+	}{
+		{"P-224", &p224.PublicKey, "unsupported JWK curve"},
+		{"DSA", mustPublicKey(t, "dsa.pem"), "unsupported key type"},
+		{"private key", mustPrivateKey(t, "ed25519.pem"), "unsupported key type"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := PublicKeyToJWK(tc.pub, "")
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("want error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
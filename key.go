@@ -0,0 +1,50 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+)
+
+// Key wraps RSA, ECDSA, Ed25519 or DSA private or public key.
+// Zero value is not usable, use WrapKey.
+type Key struct {
+	key     interface{}
+	pub     crypto.PublicKey
+	private bool
+}
+
+// WrapKey returns Key for a private key, a public key or a *x509.Certificate public key.
+func WrapKey(k interface{}) (Key, error) {
+	pub, err := toPublicKey(k)
+	if err != nil {
+		return Key{}, err
+	}
+
+	key := Key{key: k, pub: pub}
+	switch k := k.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, *dsa.PrivateKey:
+		key.private = true
+	case *x509.Certificate:
+		key.key = k.PublicKey
+	}
+	return key, nil
+}
+
+// Algorithm returns algorithm name of the key, see KeyAlgorithm.
+func (k Key) Algorithm() string { return KeyAlgorithm(k.pub) }
+
+// Size returns key size in bits, see KeySize.
+func (k Key) Size() int { return KeySize(k.pub) }
+
+// Public returns public key, for the public key it is the key itself.
+func (k Key) Public() crypto.PublicKey { return k.pub }
+
+// IsPrivate reports whether the key is a private key.
+func (k Key) IsPrivate() bool { return k.private }
+
+// Raw returns the wrapped key.
+func (k Key) Raw() interface{} { return k.key }
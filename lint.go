@@ -0,0 +1,59 @@
+package certutil
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// maxValidity is the maximal validity period of a TLS leaf certificate accepted by browsers.
+const maxValidity = 398 * 24 * time.Hour
+
+// Lint returns human-readable warnings about common certificate misconfigurations.
+// Returns empty list for a clean certificate.
+func Lint(cert *x509.Certificate) []string {
+	var warns []string
+
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+		if size := KeySize(pub); size < 2048 {
+			warns = append(warns, fmt.Sprintf("RSA key size is below 2048 bits: %d", size))
+		}
+		if IsWeakRSAExponent(pub) {
+			warns = append(warns, fmt.Sprintf("RSA public exponent is weak: %d", pub.E))
+		}
+	}
+
+	if IsWeakSignature(cert) {
+		warns = append(warns, fmt.Sprintf("signature algorithm is weak: %s", SignatureAlgorithmName(cert)))
+	}
+
+	if !CAKeyUsageValid(cert) {
+		warns = append(warns, "CA certificate key usage does not allow certificate signing")
+	}
+
+	if !IsCA(cert) {
+		if validity := cert.NotAfter.Sub(cert.NotBefore); validity > maxValidity {
+			warns = append(warns, fmt.Sprintf("validity period exceeds 398 days: %d days", int(validity.Hours()/24)))
+		}
+		if len(SANs(cert)) == 0 && cert.Subject.CommonName != "" {
+			warns = append(warns, "no subject alternative names, common name is not used for hostname verification")
+		}
+	}
+
+	if IsExpired(cert) {
+		warns = append(warns, fmt.Sprintf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339)))
+	}
+	return warns
+}
+
+// IsWeakRSAExponent reports whether RSA public exponent is less than 65537 or even.
+func IsWeakRSAExponent(pub *rsa.PublicKey) bool {
+	return pub.E < 65537 || pub.E%2 == 0
+}
+
+// CAKeyUsageValid reports whether CA certificate key usage allows certificate signing.
+// Always true for non-CA certificates.
+func CAKeyUsageValid(cert *x509.Certificate) bool {
+	return !IsCA(cert) || cert.KeyUsage&x509.KeyUsageCertSign != 0
+}
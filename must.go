@@ -0,0 +1,92 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+)
+
+// MustParseRSA is like ParseRSA but panics on error.
+// Intended for tests and initialization of package variables.
+func MustParseRSA(s string) *rsa.PrivateKey {
+	v, err := ParseRSA(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParseECDSA is like ParseECDSA but panics on error.
+func MustParseECDSA(s string) *ecdsa.PrivateKey {
+	v, err := ParseECDSA(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParseEd25519 is like ParseEd25519 but panics on error.
+func MustParseEd25519(s string) ed25519.PrivateKey {
+	v, err := ParseEd25519(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParseDSA is like ParseDSA but panics on error.
+func MustParseDSA(s string) *dsa.PrivateKey {
+	v, err := ParseDSA(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParsePrivateKey is like ParsePrivateKey but panics on error.
+func MustParsePrivateKey(s string) crypto.PrivateKey {
+	v, err := ParsePrivateKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParseX509 is like ParseX509 but panics on error.
+func MustParseX509(s string) *x509.Certificate {
+	v, err := ParseX509(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParseX509Chain is like ParseX509Chain but panics on error.
+func MustParseX509Chain(s string) []*x509.Certificate {
+	v, err := ParseX509Chain(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParseCSR is like ParseCSR but panics on error.
+func MustParseCSR(s string) *x509.CertificateRequest {
+	v, err := ParseCSR(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustParsePublicKey is like ParsePublicKey but panics on error.
+func MustParsePublicKey(s string) crypto.PublicKey {
+	v, err := ParsePublicKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package certutil
+
+import (
+	"encoding/pem"
+	"strconv"
+	"strings"
+)
+
+// DecodePEM returns the first PEM formatted block, headers are preserved.
+// Input is normalized with NormalizePEM first, as in all other PEM parsing functions.
+func DecodePEM(s string) (*pem.Block, error) {
+	block, _ := pem.Decode([]byte(NormalizePEM(s)))
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	return block, nil
+}
+
+// decodePEMType is like DecodePEM but also checks that the block is one of the given types.
+func decodePEMType(s string, types ...string) (*pem.Block, error) {
+	block, err := DecodePEM(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPEMType(block, types...); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// checkPEMType returns an error if block type is not one of the given types.
+func checkPEMType(block *pem.Block, types ...string) error {
+	wanted := make([]string, 0, len(types))
+	for _, typ := range types {
+		if block.Type == typ {
+			return nil
+		}
+		wanted = append(wanted, strconv.Quote(typ))
+	}
+	return parseErrorf("unexpected PEM type %q, wanted %s", block.Type, strings.Join(wanted, " or "))
+}
+
+// EncodePEM returns PEM encoding of the block including its headers.
+func EncodePEM(block *pem.Block) string {
+	return string(pem.EncodeToMemory(block))
+}
+
+// PEMType returns type of the first PEM formatted block, like "CERTIFICATE" or "RSA PRIVATE KEY".
+func PEMType(s string) (string, error) {
+	block, err := DecodePEM(s)
+	if err != nil {
+		return "", err
+	}
+	return block.Type, nil
+}
+
+// PEMTypes returns types of all PEM formatted blocks in order.
+func PEMTypes(s string) []string {
+	blocks, _ := SplitPEM(s)
+
+	var types []string
+	for _, block := range blocks {
+		types = append(types, block.Type)
+	}
+	return types
+}
+
+// SplitPEM returns all PEM formatted blocks in order, data between blocks is ignored.
+// Returns ErrInvalidPEM if no blocks are found.
+func SplitPEM(s string) ([]*pem.Block, error) {
+	var blocks []*pem.Block
+
+	rest := []byte(NormalizePEM(s))
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) == 0 {
+		return nil, ErrInvalidPEM
+	}
+	return blocks, nil
+}
+
+// CountPEMBlocks returns number of PEM formatted blocks by their type.
+func CountPEMBlocks(s string) map[string]int {
+	blocks, _ := SplitPEM(s)
+
+	counts := make(map[string]int, len(blocks))
+	for _, block := range blocks {
+		counts[block.Type]++
+	}
+	return counts
+}
+
+// NormalizePEM removes UTF-8 byte order mark, converts CRLF and CR line endings to LF
+// and trims surrounding whitespace. Valid PEM data is not affected.
+func NormalizePEM(s string) string {
+	s = strings.TrimPrefix(s, "\ufeff")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,494 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+var (
+	oidDataContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Certificate     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd2KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 4}
+	oidPBEWithSHAAnd128BitRC2CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 5}
+	oidPBEWithSHAAnd40BitRC2CBC      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+	oidSHA1       = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+// ParsePKCS12 private key, leaf certificate and CA certificates from DER encoded PKCS #12 (PFX) data.
+// The leaf is the certificate matching the private key.
+//
+// Supported encryption schemes are PBES2 with AES or 3DES (default in OpenSSL 3)
+// and legacy PKCS #12 PBE with 3DES or RC2. Only DER encoded data is supported.
+// Iteration counts above 10,000,000 are rejected to bound the work for untrusted input.
+// Returns ErrIncorrectPassword when the password is wrong.
+func ParsePKCS12(data []byte, password string) (crypto.PrivateKey, *x509.Certificate, []*x509.Certificate, error) {
+	bags, err := decodePKCS12(data, password)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var priv crypto.PrivateKey
+	var certs []*x509.Certificate
+	for _, bag := range bags {
+		switch {
+		case bag.ID.Equal(oidCertBag):
+			var cb struct {
+				ID   asn1.ObjectIdentifier
+				Data []byte `asn1:"tag:0,explicit"`
+			}
+			if err := unmarshalDER(bag.Value.Bytes, &cb); err != nil {
+				return nil, nil, nil, safeError("parse PKCS #12 cert bag", err)
+			}
+			if !cb.ID.Equal(oidX509Certificate) {
+				continue
+			}
+			cert, err := parseX509(cb.Data)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			certs = append(certs, cert)
+
+		case bag.ID.Equal(oidKeyBag), bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+			if priv != nil {
+				continue
+			}
+			der := bag.Value.Bytes
+			if bag.ID.Equal(oidPKCS8ShroudedKeyBag) {
+				var epki struct {
+					Algorithm     pkix.AlgorithmIdentifier
+					EncryptedData []byte
+				}
+				if err := unmarshalDER(der, &epki); err != nil {
+					return nil, nil, nil, safeError("parse PKCS #12 key bag", err)
+				}
+				if der, err = pbeDecrypt(epki.Algorithm, epki.EncryptedData, password); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			if priv, err = parsePrivateKey(der); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+
+	if priv == nil {
+		return nil, nil, nil, errors.New("certutil: no private key found in PKCS #12")
+	}
+
+	var leaf *x509.Certificate
+	var caCerts []*x509.Certificate
+	for _, cert := range certs {
+		if leaf == nil {
+			if ok, _ := KeysMatch(priv, cert); ok {
+				leaf = cert
+				continue
+			}
+		}
+		caCerts = append(caCerts, cert)
+	}
+
+	if leaf == nil {
+		return nil, nil, nil, errors.New("certutil: no certificate for private key found in PKCS #12")
+	}
+	return priv, leaf, caCerts, nil
+}
+
+type pkcs12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type pkcs12SafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue   `asn1:"tag:0,explicit"`
+	Attributes []asn1.RawValue `asn1:"set,optional"`
+}
+
+// decodePKCS12 verifies MAC and returns all safe bags.
+func decodePKCS12(data []byte, password string) ([]pkcs12SafeBag, error) {
+	var pfx struct {
+		Version  int
+		AuthSafe pkcs12ContentInfo
+		MacData  struct {
+			Mac struct {
+				Algorithm pkix.AlgorithmIdentifier
+				Digest    []byte
+			}
+			MacSalt    []byte
+			Iterations int `asn1:"optional,default:1"`
+		} `asn1:"optional"`
+	}
+	if err := unmarshalDER(data, &pfx); err != nil {
+		return nil, safeError("parse PKCS #12", err)
+	}
+	if pfx.Version != 3 {
+		return nil, fmt.Errorf("certutil: unsupported PKCS #12 version: %d", pfx.Version)
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return nil, errors.New("certutil: only password-protected PKCS #12 is supported")
+	}
+
+	var authSafe []byte
+	if err := unmarshalDER(pfx.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return nil, safeError("parse PKCS #12", err)
+	}
+
+	if len(pfx.MacData.Mac.Digest) > 0 {
+		newHash, err := hashByOID(pfx.MacData.Mac.Algorithm.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIterations(pfx.MacData.Iterations); err != nil {
+			return nil, err
+		}
+		key := pkcs12KDF(newHash, bmpPassword(password), pfx.MacData.MacSalt, pfx.MacData.Iterations, 3, newHash().Size())
+
+		mac := hmac.New(newHash, key)
+		mac.Write(authSafe)
+		if !hmac.Equal(mac.Sum(nil), pfx.MacData.Mac.Digest) {
+			return nil, ErrIncorrectPassword
+		}
+	}
+
+	var contents []pkcs12ContentInfo
+	if err := unmarshalDER(authSafe, &contents); err != nil {
+		return nil, safeError("parse PKCS #12", err)
+	}
+
+	var bags []pkcs12SafeBag
+	for _, ci := range contents {
+		var safeContents []byte
+		switch {
+		case ci.ContentType.Equal(oidDataContentType):
+			if err := unmarshalDER(ci.Content.Bytes, &safeContents); err != nil {
+				return nil, safeError("parse PKCS #12", err)
+			}
+
+		case ci.ContentType.Equal(oidEncryptedDataContentType):
+			var ed struct {
+				Version              int
+				EncryptedContentInfo struct {
+					ContentType      asn1.ObjectIdentifier
+					Algorithm        pkix.AlgorithmIdentifier
+					EncryptedContent []byte `asn1:"tag:0,optional"`
+				}
+			}
+			if err := unmarshalDER(ci.Content.Bytes, &ed); err != nil {
+				return nil, safeError("parse PKCS #12", err)
+			}
+
+			var err error
+			info := ed.EncryptedContentInfo
+			safeContents, err = pbeDecrypt(info.Algorithm, info.EncryptedContent, password)
+			if err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, errors.New("certutil: only data and encrypted data are supported in PKCS #12")
+		}
+
+		var part []pkcs12SafeBag
+		if err := unmarshalDER(safeContents, &part); err != nil {
+			return nil, safeError("parse PKCS #12 safe contents", err)
+		}
+		bags = append(bags, part...)
+	}
+	return bags, nil
+}
+
+// pbeDecrypt decrypts data with PKCS #12 PBE or PBES2 scheme.
+func pbeDecrypt(algo pkix.AlgorithmIdentifier, data []byte, password string) ([]byte, error) {
+	var block cipher.Block
+	var iv []byte
+
+	switch {
+	case algo.Algorithm.Equal(oidPBES2):
+		var err error
+		if block, iv, err = pbes2Cipher(algo.Parameters.FullBytes, password); err != nil {
+			return nil, err
+		}
+
+	default:
+		var params struct {
+			Salt       []byte
+			Iterations int
+		}
+		if err := unmarshalDER(algo.Parameters.FullBytes, &params); err != nil {
+			return nil, safeError("parse PBE parameters", err)
+		}
+		if err := checkIterations(params.Iterations); err != nil {
+			return nil, err
+		}
+
+		pass := bmpPassword(password)
+		kdf := func(id byte, size int) []byte {
+			return pkcs12KDF(sha1.New, pass, params.Salt, params.Iterations, id, size)
+		}
+
+		var err error
+		switch {
+		case algo.Algorithm.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC):
+			block, err = des.NewTripleDESCipher(kdf(1, 24))
+			iv = kdf(2, 8)
+		case algo.Algorithm.Equal(oidPBEWithSHAAnd2KeyTripleDESCBC):
+			key := kdf(1, 16)
+			block, err = des.NewTripleDESCipher(append(key, key[:8]...))
+			iv = kdf(2, 8)
+		case algo.Algorithm.Equal(oidPBEWithSHAAnd128BitRC2CBC):
+			block, err = newRC2Cipher(kdf(1, 16), 128)
+			iv = kdf(2, 8)
+		case algo.Algorithm.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+			block, err = newRC2Cipher(kdf(1, 5), 40)
+			iv = kdf(2, 8)
+		default:
+			return nil, fmt.Errorf("certutil: unsupported encryption algorithm: %s", algo.Algorithm)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, errors.New("certutil: invalid encrypted data length")
+	}
+
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+
+	// without MAC a wrong password is detected only by broken padding.
+	pad := int(out[len(out)-1])
+	if pad == 0 || pad > block.BlockSize() || !bytes.Equal(out[len(out)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, ErrIncorrectPassword
+	}
+	return out[:len(out)-pad], nil
+}
+
+// pbes2Cipher returns cipher and IV for PBES2 parameters (RFC 8018).
+func pbes2Cipher(params []byte, password string) (cipher.Block, []byte, error) {
+	var pbes2 struct {
+		KDF        pkix.AlgorithmIdentifier
+		Encryption pkix.AlgorithmIdentifier
+	}
+	if err := unmarshalDER(params, &pbes2); err != nil {
+		return nil, nil, safeError("parse PBES2 parameters", err)
+	}
+	if !pbes2.KDF.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, fmt.Errorf("certutil: unsupported key derivation function: %s", pbes2.KDF.Algorithm)
+	}
+
+	var kdf struct {
+		Salt       []byte
+		Iterations int
+		KeyLength  int                      `asn1:"optional"`
+		PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+	}
+	if err := unmarshalDER(pbes2.KDF.Parameters.FullBytes, &kdf); err != nil {
+		return nil, nil, safeError("parse PBKDF2 parameters", err)
+	}
+	if err := checkIterations(kdf.Iterations); err != nil {
+		return nil, nil, err
+	}
+
+	newHash := sha1.New
+	if len(kdf.PRF.Algorithm) > 0 {
+		switch {
+		case kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+			newHash = sha256.New
+		case kdf.PRF.Algorithm.Equal(oidHMACWithSHA384):
+			newHash = sha512.New384
+		case kdf.PRF.Algorithm.Equal(oidHMACWithSHA512):
+			newHash = sha512.New
+		default:
+			return nil, nil, fmt.Errorf("certutil: unsupported PBKDF2 PRF: %s", kdf.PRF.Algorithm)
+		}
+	}
+
+	var keySize int
+	var newCipher func(key []byte) (cipher.Block, error)
+	switch enc := pbes2.Encryption.Algorithm; {
+	case enc.Equal(oidAES128CBC):
+		keySize, newCipher = 16, aes.NewCipher
+	case enc.Equal(oidAES192CBC):
+		keySize, newCipher = 24, aes.NewCipher
+	case enc.Equal(oidAES256CBC):
+		keySize, newCipher = 32, aes.NewCipher
+	case enc.Equal(oidDESEDE3CBC):
+		keySize, newCipher = 24, des.NewTripleDESCipher
+	default:
+		return nil, nil, fmt.Errorf("certutil: unsupported encryption algorithm: %s", enc)
+	}
+
+	var iv []byte
+	if err := unmarshalDER(pbes2.Encryption.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, safeError("parse PBES2 IV", err)
+	}
+
+	// PBES2 in PKCS #12 uses password as UTF-8 bytes, not as BMPString.
+	key := pbkdf2([]byte(password), kdf.Salt, kdf.Iterations, keySize, newHash)
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, nil, errors.New("certutil: invalid PBES2 IV length")
+	}
+	return block, iv, nil
+}
+
+// maxPBEIterations is a limit for MAC and PBE iteration counts, OpenSSL uses 2048 by default.
+const maxPBEIterations = 10000000
+
+func checkIterations(n int) error {
+	if n < 1 || n > maxPBEIterations {
+		return fmt.Errorf("certutil: unsupported PKCS #12 iteration count: %d", n)
+	}
+	return nil
+}
+
+func hashByOID(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidSHA512):
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("certutil: unsupported MAC algorithm: %s", oid)
+	}
+}
+
+// pkcs12KDF derives key material as described in RFC 7292, appendix B.2.
+func pkcs12KDF(newHash func() hash.Hash, pass, salt []byte, iterations int, id byte, size int) []byte {
+	h := newHash()
+	u := h.Size()
+	v := h.BlockSize()
+
+	fill := func(b []byte) []byte {
+		if len(b) == 0 {
+			return nil
+		}
+		n := v * ((len(b) + v - 1) / v)
+		res := make([]byte, n)
+		for i := range res {
+			res[i] = b[i%len(b)]
+		}
+		return res
+	}
+
+	D := bytes.Repeat([]byte{id}, v)
+	I := append(fill(salt), fill(pass)...)
+
+	res := make([]byte, 0, size+u)
+	for len(res) < size {
+		h.Reset()
+		h.Write(D)
+		h.Write(I)
+		A := h.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(A)
+			A = h.Sum(A[:0])
+		}
+		res = append(res, A...)
+
+		// I_j = (I_j + B + 1) mod 2^(v*8) for each v-byte block of I.
+		B := fill(A)
+		for j := 0; j < len(I); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(I[j+k]) + int(B[k]) + carry
+				I[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return res[:size]
+}
+
+// pbkdf2 derives a key as described in RFC 8018, section 5.2.
+func pbkdf2(password, salt []byte, iterations, size int, newHash func() hash.Hash) []byte {
+	prf := hmac.New(newHash, password)
+	res := make([]byte, 0, size+prf.Size())
+
+	for block := uint32(1); len(res) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		U := prf.Sum(nil)
+		T := append([]byte(nil), U...)
+
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(U)
+			U = prf.Sum(U[:0])
+			for k := range T {
+				T[k] ^= U[k]
+			}
+		}
+		res = append(res, T...)
+	}
+	return res[:size]
+}
+
+// bmpPassword encodes password as null-terminated BMPString (UTF-16 big endian).
+func bmpPassword(password string) []byte {
+	codes := utf16.Encode([]rune(password))
+	res := make([]byte, 0, 2*len(codes)+2)
+	for _, c := range codes {
+		res = append(res, byte(c>>8), byte(c))
+	}
+	return append(res, 0, 0)
+}
+
+func unmarshalDER(data []byte, v interface{}) error {
+	rest, err := asn1.Unmarshal(data, v)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("trailing data")
+	}
+	return nil
+}
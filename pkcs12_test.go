@@ -0,0 +1,252 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strings"
+	"testing"
+)
+
+func TestParsePKCS12(t *testing.T) {
+	testCases := []struct {
+		file   string
+		scheme string
+	}{
+		{"aes256.p12", "PBES2 AES-256-CBC, MAC SHA-256"},
+		{"aes128.p12", "PBES2 AES-128-CBC and AES-192-CBC, MAC SHA-512"},
+		{"pbes2_3des.p12", "PBES2 DES-EDE3-CBC, MAC SHA-1"},
+		{"legacy_rc2_40.p12", "PBE SHA-1 3DES and RC2-40"},
+		{"legacy_rc2_128.p12", "PBE SHA-1 3DES and RC2-128"},
+		{"legacy_2des.p12", "PBE SHA-1 2DES and 3DES"},
+		{"nomac.p12", "PBES2 AES-256-CBC, no MAC"},
+	}
+
+	wantKey := mustPrivateKey(t, "rsa.pem")
+	wantLeaf := mustCertificate(t, "leaf.pem")
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			data := readTestdataBytes(t, tc.file)
+
+			priv, leaf, caCerts, err := ParsePKCS12(data, "secret")
+			if err != nil {
+				t.Fatalf("%s: %v", tc.scheme, err)
+			}
+			if ok, _ := SameKeyPair(priv, wantKey); !ok {
+				t.Fatal("private key does not match")
+			}
+			if !CertificatesEqual(leaf, wantLeaf) {
+				t.Fatal("leaf certificate does not match")
+			}
+			if len(caCerts) != 2 {
+				t.Fatalf("want 2 CA certificates, got %d", len(caCerts))
+			}
+
+			// without MAC it relies on the padding check, which is stable for a fixed fixture.
+			_, _, _, err = ParsePKCS12(data, "wrong")
+			if !errors.Is(err, ErrIncorrectPassword) {
+				t.Fatalf("want ErrIncorrectPassword, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParsePKCS12Invalid(t *testing.T) {
+	_, _, _, err := ParsePKCS12([]byte("junk"), "secret")
+	if !IsParseError(err) {
+		t.Fatalf("want parse error, got %v", err)
+	}
+
+	data := readTestdataBytes(t, "aes256.p12")
+	_, _, _, err = ParsePKCS12(data[:len(data)-1], "secret")
+	if !IsParseError(err) {
+		t.Fatalf("want parse error, got %v", err)
+	}
+}
+
+func TestParsePKCS12Iterations(t *testing.T) {
+	var pfx struct {
+		Version  int
+		AuthSafe asn1.RawValue
+		MacData  struct {
+			Mac        asn1.RawValue
+			MacSalt    []byte
+			Iterations int
+		}
+	}
+	if err := unmarshalDER(readTestdataBytes(t, "aes256.p12"), &pfx); err != nil {
+		t.Fatal(err)
+	}
+	pfx.MacData.Iterations = maxPBEIterations + 1
+	data, err := asn1.Marshal(pfx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, _, err = ParsePKCS12(data, "secret")
+	checkIterationsError(t, err)
+
+	legacyParams, err := asn1.Marshal(struct {
+		Salt       []byte
+		Iterations int
+	}{[]byte("saltsalt"), 1 << 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	algo := pkix.AlgorithmIdentifier{
+		Algorithm:  oidPBEWithSHAAnd3KeyTripleDESCBC,
+		Parameters: asn1.RawValue{FullBytes: legacyParams},
+	}
+	_, err = pbeDecrypt(algo, make([]byte, 16), "secret")
+	checkIterationsError(t, err)
+
+	pbkdf2Params, err := asn1.Marshal(struct {
+		Salt       []byte
+		Iterations int
+	}{[]byte("saltsalt"), 1 << 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pbes2Params, err := asn1.Marshal(struct {
+		KDF        pkix.AlgorithmIdentifier
+		Encryption pkix.AlgorithmIdentifier
+	}{
+		KDF:        pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: pbkdf2Params}},
+		Encryption: pkix.AlgorithmIdentifier{Algorithm: oidAES128CBC},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = pbes2Cipher(pbes2Params, "secret")
+	checkIterationsError(t, err)
+}
+
+func checkIterationsError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), "iteration count") {
+		t.Fatalf("want iteration count error, got %v", err)
+	}
+}
+
+func TestPKCS12KDF(t *testing.T) {
+	// generated with: openssl kdf -keylen N -kdfopt digest:D -kdfopt hexpass:P
+	// -kdfopt hexsalt:S -kdfopt iter:I -kdfopt id:ID PKCS12KDF
+	testCases := []struct {
+		newHash    func() hash.Hash
+		password   string
+		salt       string
+		iterations int
+		id         byte
+		want       string
+	}{
+		{sha1.New, "sesame", "ffffffffffffffff", 2048, 1, "7cd9fd3e2b3be7691a44e3bef0f9ea0fb9b897d4e325d9d1"},
+		{sha1.New, "sesame", "ffffffffffffffff", 2048, 2, "3f5a277f9c21ff82b0d22f41c70f72d36d6c1e365247094a"},
+		{sha1.New, "sesame", "ffffffffffffffff", 2048, 3, "91715bd27aa978513e3a40f55b8f7b567b5a9f3c4279edab"},
+		{sha256.New, "sesame", hex.EncodeToString([]byte("saltsalt")), 1, 3, "1f4d65a5f5c294bcde39d5fbc8740ca16371315466ece97d34183790032354db"},
+	}
+
+	for _, tc := range testCases {
+		salt := mustHex(t, tc.salt)
+		want := mustHex(t, tc.want)
+
+		got := pkcs12KDF(tc.newHash, bmpPassword(tc.password), salt, tc.iterations, tc.id, len(want))
+		if !bytes.Equal(got, want) {
+			t.Errorf("id %d: want %x, got %x", tc.id, want, got)
+		}
+	}
+}
+
+func TestBMPPassword(t *testing.T) {
+	got := bmpPassword("sesame")
+	want := mustHex(t, "0073006500730061006d00650000")
+	if !bytes.Equal(got, want) {
+		t.Fatalf("want %x, got %x", want, got)
+	}
+	if got := bmpPassword(""); !bytes.Equal(got, []byte{0, 0}) {
+		t.Fatalf("want 0000, got %x", got)
+	}
+}
+
+func TestPBKDF2(t *testing.T) {
+	// RFC 6070 test vectors for HMAC-SHA1 and the common HMAC-SHA256 vector.
+	testCases := []struct {
+		newHash    func() hash.Hash
+		password   string
+		salt       string
+		iterations int
+		want       string
+	}{
+		{sha1.New, "password", "salt", 1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{sha1.New, "password", "salt", 2, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{sha1.New, "password", "salt", 4096, "4b007901b765489abead49d926f721d065a429c1"},
+		{
+			sha1.New, "passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096,
+			"3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038",
+		},
+		{sha1.New, "pass\x00word", "sa\x00lt", 4096, "56fa6aa75548099dcc37d7f03425e0c3"},
+		{sha256.New, "password", "salt", 4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	}
+
+	for _, tc := range testCases {
+		want := mustHex(t, tc.want)
+		got := pbkdf2([]byte(tc.password), []byte(tc.salt), tc.iterations, len(want), tc.newHash)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%q/%q/%d: want %x, got %x", tc.password, tc.salt, tc.iterations, want, got)
+		}
+	}
+}
+
+func TestRC2(t *testing.T) {
+	// RFC 2268, section 5.
+	testCases := []struct {
+		key        string
+		bits       int
+		plaintext  string
+		ciphertext string
+	}{
+		{"0000000000000000", 63, "0000000000000000", "ebb773f993278eff"},
+		{"ffffffffffffffff", 64, "ffffffffffffffff", "278b27e42e2f0d49"},
+		{"3000000000000000", 64, "1000000000000001", "30649edf9be7d2c2"},
+		{"88", 64, "0000000000000000", "61a8a244adacccf0"},
+		{"88bca90e90875a", 64, "0000000000000000", "6ccf4308974c267f"},
+		{"88bca90e90875a7f0f79c384627bafb2", 64, "0000000000000000", "1a807d272bbe5db1"},
+		{"88bca90e90875a7f0f79c384627bafb2", 128, "0000000000000000", "2269552ab0f85ca6"},
+		{
+			"88bca90e90875a7f0f79c384627bafb216f80a6f85920584c42fceb0be255daf1e", 129,
+			"0000000000000000", "5b78d3a43dfff1f1",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := newRC2Cipher(mustHex(t, tc.key), tc.bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := mustHex(t, tc.plaintext)
+		got := make([]byte, c.BlockSize())
+		c.Decrypt(got, mustHex(t, tc.ciphertext))
+		if !bytes.Equal(got, want) {
+			t.Errorf("key %s/%d: want %x, got %x", tc.key, tc.bits, want, got)
+		}
+	}
+
+	if _, err := newRC2Cipher(nil, 64); err == nil {
+		t.Fatal("want error for empty key")
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
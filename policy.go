@@ -0,0 +1,73 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+)
+
+// KeyPolicy checks a parsed public key, see ParsePublicKeyWithPolicy.
+type KeyPolicy func(pub crypto.PublicKey) error
+
+// AllowTypes accepts only keys of the given algorithms, like "RSA" or "Ed25519".
+// See KeyAlgorithm for the names.
+func AllowTypes(algorithms ...string) KeyPolicy {
+	return func(pub crypto.PublicKey) error {
+		algo := KeyAlgorithm(pub)
+		for _, a := range algorithms {
+			if a == algo {
+				return nil
+			}
+		}
+		return fmt.Errorf("certutil: key type is not allowed: %s", algo)
+	}
+}
+
+// MinRSABits rejects RSA keys smaller than the given size in bits. Other key types are accepted.
+func MinRSABits(bits int) KeyPolicy {
+	return func(pub crypto.PublicKey) error {
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil
+		}
+		if size := KeySize(key); size < bits {
+			return fmt.Errorf("certutil: RSA key size %d is less than %d", size, bits)
+		}
+		return nil
+	}
+}
+
+// AllowCurves accepts only ECDSA keys on the given curves. Other key types are accepted.
+func AllowCurves(curves ...elliptic.Curve) KeyPolicy {
+	return func(pub crypto.PublicKey) error {
+		key, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return nil
+		}
+		name := CurveName(key)
+		for _, curve := range curves {
+			if curve.Params().Name == name {
+				return nil
+			}
+		}
+		return fmt.Errorf("certutil: curve is not allowed: %s", name)
+	}
+}
+
+// ParsePublicKeyWithPolicy is like ParsePublicKey but returns an error
+// if the key violates any of the given policies.
+func ParsePublicKeyWithPolicy(s string, policies ...KeyPolicy) (crypto.PublicKey, error) {
+	pub, err := ParsePublicKey(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, policy := range policies {
+		if err := policy(pub); err != nil {
+			return nil, err
+		}
+	}
+	return pub, nil
+}
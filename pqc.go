@@ -0,0 +1,75 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+)
+
+// PQCPublicKey is an opaque post-quantum public key, see ParsePublicKeyPQC.
+//
+// EXPERIMENTAL: this type and related functions may change or be removed without notice.
+type PQCPublicKey struct {
+	Algorithm string // like "ML-DSA-65".
+	OID       asn1.ObjectIdentifier
+	Bytes     []byte // raw public key, not validated.
+}
+
+// ParsePublicKeyPQC recognizes ML-DSA, SLH-DSA and ML-KEM PKIX public keys from a "PUBLIC KEY" PEM formatted block.
+// Key is returned as *PQCPublicKey as is, without any validation,
+// it cannot be used for signature verification or encryption.
+//
+// EXPERIMENTAL: intended for key inventory only, may change or be removed without notice.
+func ParsePublicKeyPQC(s string) (crypto.PublicKey, error) {
+	block, err := decodePEMType(s, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if err := unmarshalDER(block.Bytes, &spki); err != nil {
+		return nil, safeError("parse public key", err)
+	}
+
+	oid := spki.Algorithm.Algorithm
+	for _, algo := range pqcAlgorithms {
+		if oid.Equal(algo.oid) {
+			key := &PQCPublicKey{
+				Algorithm: algo.name,
+				OID:       oid,
+				Bytes:     spki.PublicKey.RightAlign(),
+			}
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("certutil: unsupported post-quantum algorithm: %s", oid)
+}
+
+// pqcAlgorithms from NIST Computer Security Objects Register.
+var pqcAlgorithms = []struct {
+	oid  asn1.ObjectIdentifier
+	name string
+}{
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 17}, "ML-DSA-44"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 18}, "ML-DSA-65"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 19}, "ML-DSA-87"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 20}, "SLH-DSA-SHA2-128s"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 21}, "SLH-DSA-SHA2-128f"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 22}, "SLH-DSA-SHA2-192s"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 23}, "SLH-DSA-SHA2-192f"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 24}, "SLH-DSA-SHA2-256s"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 25}, "SLH-DSA-SHA2-256f"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 26}, "SLH-DSA-SHAKE-128s"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 27}, "SLH-DSA-SHAKE-128f"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 28}, "SLH-DSA-SHAKE-192s"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 29}, "SLH-DSA-SHAKE-192f"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 30}, "SLH-DSA-SHAKE-256s"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 31}, "SLH-DSA-SHAKE-256f"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1}, "ML-KEM-512"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}, "ML-KEM-768"},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}, "ML-KEM-1024"},
+}
@@ -0,0 +1,53 @@
+package certutil
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+)
+
+// oidExtensionSCT is the embedded SCT list extension from RFC 6962, section 3.3.
+var oidExtensionSCT = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// HasEmbeddedSCT reports whether certificate has at least one embedded Signed Certificate Timestamp.
+func HasEmbeddedSCT(cert *x509.Certificate) bool {
+	n, err := SCTCount(cert)
+	return err == nil && n > 0
+}
+
+// SCTCount returns number of Signed Certificate Timestamps embedded in the certificate.
+// Returns 0 if extension is missing and an error if the SCT list is malformed.
+func SCTCount(cert *x509.Certificate) (int, error) {
+	value, ok := Extension(cert, oidExtensionSCT)
+	if !ok {
+		return 0, nil
+	}
+	return countSCTs(value)
+}
+
+// countSCTs parses TLS encoded SignedCertificateTimestampList wrapped in OCTET STRING.
+func countSCTs(value []byte) (int, error) {
+	var list []byte
+	if err := unmarshalDER(value, &list); err != nil {
+		return 0, safeError("parse SCT list", err)
+	}
+
+	errMalformed := errors.New("certutil: malformed SCT list")
+	if len(list) < 2 || int(binary.BigEndian.Uint16(list)) != len(list)-2 {
+		return 0, errMalformed
+	}
+
+	count := 0
+	for list = list[2:]; len(list) > 0; count++ {
+		if len(list) < 2 {
+			return 0, errMalformed
+		}
+		size := int(binary.BigEndian.Uint16(list))
+		if size == 0 || len(list)-2 < size {
+			return 0, errMalformed
+		}
+		list = list[2+size:]
+	}
+	return count, nil
+}
@@ -0,0 +1,113 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Sign message with RSA, ECDSA or Ed25519 private key.
+// RSA uses PKCS #1 v1.5 with SHA-256, ECDSA uses ASN.1 signature with SHA-256, Ed25519 signs message as is.
+func Sign(priv crypto.PrivateKey, message []byte) ([]byte, error) {
+	switch priv := priv.(type) {
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		return rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		return ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	case ed25519.PrivateKey:
+		return ed25519.Sign(priv, message), nil
+	default:
+		return nil, fmt.Errorf("certutil: unsupported key type: %T", priv)
+	}
+}
+
+// Verify signature of the message with RSA, ECDSA or Ed25519 public key.
+// See Sign for the algorithms. Returns ErrInvalidSignature if signature is not valid.
+func Verify(pub crypto.PublicKey, message, sig []byte) error {
+	var ok bool
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		ok = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, message, sig)
+	default:
+		return fmt.Errorf("certutil: unsupported key type: %T", pub)
+	}
+
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyWithPEM verifies signature of the message with a PEM formatted public key or certificate.
+// See Verify for the algorithms. Returns ErrInvalidSignature if signature is not valid
+// and an error reported by IsParseError if the key cannot be parsed.
+func VerifyWithPEM(pubPEM string, message, sig []byte) error {
+	pub, err := ParsePublicKey(pubPEM)
+	if err != nil {
+		return err
+	}
+	return Verify(pub, message, sig)
+}
+
+// ECDSASigToRaw converts ASN.1 DER encoded ECDSA signature to fixed-width r||s form used by JOSE.
+// Each of r and s is left-padded with zeros to the curve byte length.
+func ECDSASigToRaw(sig []byte, curve elliptic.Curve) ([]byte, error) {
+	var esig ecdsaSignature
+	if err := unmarshalDER(sig, &esig); err != nil {
+		return nil, safeError("parse ECDSA signature", err)
+	}
+
+	size := curveByteLen(curve)
+	for _, v := range []*big.Int{esig.R, esig.S} {
+		if v.Sign() <= 0 || (v.BitLen()+7)/8 > size {
+			return nil, errors.New("certutil: invalid ECDSA signature")
+		}
+	}
+
+	raw := make([]byte, 2*size)
+	esig.R.FillBytes(raw[:size])
+	esig.S.FillBytes(raw[size:])
+	return raw, nil
+}
+
+// ECDSASigFromRaw converts fixed-width r||s ECDSA signature to ASN.1 DER encoding.
+// Length of the signature must be twice the curve byte length.
+func ECDSASigFromRaw(raw []byte, curve elliptic.Curve) ([]byte, error) {
+	size := curveByteLen(curve)
+	if len(raw) != 2*size {
+		return nil, fmt.Errorf("certutil: invalid ECDSA signature length: %d, wanted %d", len(raw), 2*size)
+	}
+
+	r := new(big.Int).SetBytes(raw[:size])
+	s := new(big.Int).SetBytes(raw[size:])
+	if r.Sign() == 0 || s.Sign() == 0 {
+		return nil, errors.New("certutil: invalid ECDSA signature")
+	}
+
+	return asn1.Marshal(ecdsaSignature{R: r, S: s})
+}
+
+// ecdsaSignature is ASN.1 structure of ECDSA signature from RFC 3279.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+func curveByteLen(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
@@ -0,0 +1,125 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+)
+
+func TestECDSASigRaw(t *testing.T) {
+	testCases := []struct {
+		name    string
+		curve   elliptic.Curve
+		rawSize int
+	}{
+		{"P-256", elliptic.P256(), 64},
+		{"P-384", elliptic.P384(), 96},
+	}
+
+	digest := sha256.Sum256([]byte("hello"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := ECDSASigToRaw(sig, tc.curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(raw) != tc.rawSize {
+				t.Fatalf("want %d bytes, got %d", tc.rawSize, len(raw))
+			}
+			r := new(big.Int).SetBytes(raw[:tc.rawSize/2])
+			s := new(big.Int).SetBytes(raw[tc.rawSize/2:])
+			if !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+				t.Fatal("raw signature does not verify")
+			}
+
+			der, err := ECDSASigFromRaw(raw, tc.curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(der, sig) {
+				t.Fatal("round-trip signature does not match")
+			}
+		})
+	}
+}
+
+func TestECDSASigRawPadding(t *testing.T) {
+	curve := elliptic.P256()
+	// r and s are much shorter than 32 bytes, so both must be left-padded.
+	sig, err := asn1.Marshal(ecdsaSignature{R: big.NewInt(1), S: big.NewInt(0x0102)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := ECDSASigToRaw(sig, curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, 64)
+	want[31] = 0x01
+	want[62], want[63] = 0x01, 0x02
+	if !bytes.Equal(raw, want) {
+		t.Fatalf("want %x, got %x", want, raw)
+	}
+
+	der, err := ECDSASigFromRaw(raw, curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(der, sig) {
+		t.Fatalf("want %x, got %x", sig, der)
+	}
+}
+
+func TestECDSASigRawInvalid(t *testing.T) {
+	p256, p384 := elliptic.P256(), elliptic.P384()
+
+	if _, err := ECDSASigFromRaw(make([]byte, 63), p256); err == nil {
+		t.Fatal("want error for short signature")
+	}
+	if _, err := ECDSASigFromRaw(make([]byte, 96), p256); err == nil {
+		t.Fatal("want error for P-384 length on P-256")
+	}
+	if _, err := ECDSASigFromRaw(make([]byte, 64), p256); err == nil {
+		t.Fatal("want error for zero r and s")
+	}
+
+	// 48 byte r does not fit into P-256.
+	big384 := new(big.Int).Lsh(big.NewInt(1), 383)
+	sig, err := asn1.Marshal(ecdsaSignature{R: big384, S: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ECDSASigToRaw(sig, p256); err == nil {
+		t.Fatal("want error for too long r")
+	}
+	if _, err := ECDSASigToRaw(sig, p384); err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err = asn1.Marshal(ecdsaSignature{R: big.NewInt(0), S: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ECDSASigToRaw(sig, p256); err == nil {
+		t.Fatal("want error for zero r")
+	}
+	if _, err := ECDSASigToRaw([]byte("junk"), p256); err == nil {
+		t.Fatal("want error for invalid DER")
+	}
+}
@@ -0,0 +1,168 @@
+package certutil
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ParsePublicKeyFromSSH RSA, ECDSA and Ed25519 public keys from OpenSSH authorized_keys format.
+// Only the first key in the input is parsed, options and comments are ignored.
+func ParsePublicKeyFromSSH(s string) (crypto.PublicKey, error) {
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields)-1; i++ {
+			if !isSSHKeyType(fields[i]) {
+				continue
+			}
+
+			blob, err := base64.StdEncoding.DecodeString(fields[i+1])
+			if err != nil {
+				return nil, safeError("decode SSH key", err)
+			}
+			return parseSSHKey(fields[i], blob)
+		}
+		return nil, parseErrorf("invalid SSH public key")
+	}
+	return nil, parseErrorf("no SSH public key found")
+}
+
+func isSSHKeyType(s string) bool {
+	switch s {
+	case "ssh-rsa", "ssh-ed25519",
+		"ecdsa-sha2-nistp256", "ecdsa-sha2-nistp384", "ecdsa-sha2-nistp521":
+		return true
+	default:
+		return false
+	}
+}
+
+func parseSSHKey(keyType string, blob []byte) (crypto.PublicKey, error) {
+	r := sshReader{b: blob}
+
+	algo := string(r.next())
+	if r.err != nil {
+		return nil, r.err
+	}
+	if algo != keyType {
+		return nil, parseErrorf("SSH key types do not match: %s and %s", keyType, algo)
+	}
+
+	switch algo {
+	case "ssh-rsa":
+		e := r.nextInt()
+		n := r.nextInt()
+		if err := r.done(); err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, parseErrorf("invalid SSH RSA exponent")
+		}
+		if n.Sign() <= 0 {
+			return nil, parseErrorf("invalid SSH RSA modulus")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "ecdsa-sha2-nistp256", "ecdsa-sha2-nistp384", "ecdsa-sha2-nistp521":
+		name := string(r.next())
+		if r.err != nil {
+			return nil, r.err
+		}
+		if algo != "ecdsa-sha2-"+name {
+			return nil, parseErrorf("SSH key type and curve do not match: %s and %s", algo, name)
+		}
+
+		var curve elliptic.Curve
+		switch name {
+		case "nistp256":
+			curve = elliptic.P256()
+		case "nistp384":
+			curve = elliptic.P384()
+		case "nistp521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("certutil: unsupported SSH curve: %s", name)
+		}
+		point := r.next()
+		if err := r.done(); err != nil {
+			return nil, err
+		}
+
+		x, y := elliptic.Unmarshal(curve, point)
+		if x == nil {
+			return nil, parseErrorf("invalid SSH ECDSA point")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "ssh-ed25519":
+		key := r.next()
+		if err := r.done(); err != nil {
+			return nil, err
+		}
+		if len(key) != ed25519.PublicKeySize {
+			return nil, parseErrorf("invalid SSH Ed25519 key size")
+		}
+		return ed25519.PublicKey(key), nil
+
+	default:
+		return nil, fmt.Errorf("certutil: unsupported SSH key type: %s", algo)
+	}
+}
+
+// sshReader reads length-prefixed strings from SSH wire format (RFC 4251).
+type sshReader struct {
+	b   []byte
+	err error
+}
+
+func (r *sshReader) next() []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.b) < 4 {
+		r.err = parseErrorf("truncated SSH key")
+		return nil
+	}
+	n := binary.BigEndian.Uint32(r.b)
+	if uint32(len(r.b)-4) < n {
+		r.err = parseErrorf("truncated SSH key")
+		return nil
+	}
+	v := r.b[4 : 4+n]
+	r.b = r.b[4+n:]
+	return v
+}
+
+// nextInt reads mpint, negative values are rejected.
+func (r *sshReader) nextInt() *big.Int {
+	b := r.next()
+	if r.err == nil && len(b) > 0 && b[0]&0x80 != 0 {
+		r.err = parseErrorf("negative SSH integer")
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// done returns read error or an error if there are bytes left after the last field.
+func (r *sshReader) done() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.b) != 0 {
+		return parseErrorf("trailing data in SSH key")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package certutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestParsePublicKeyFromSSH(t *testing.T) {
+	testCases := []struct {
+		file  string
+		algo  string
+		size  int
+		curve string
+	}{
+		{"ssh_rsa.pub", "RSA", 2048, ""},
+		{"ssh_ecdsa_256.pub", "ECDSA", 256, "P-256"},
+		{"ssh_ecdsa_384.pub", "ECDSA", 384, "P-384"},
+		{"ssh_ecdsa_521.pub", "ECDSA", 521, "P-521"},
+		{"ssh_ed25519.pub", "Ed25519", 256, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			pub, err := ParsePublicKeyFromSSH(readTestdata(t, tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := KeyAlgorithm(pub); got != tc.algo {
+				t.Fatalf("want %s, got %s", tc.algo, got)
+			}
+			if got := KeySize(pub); got != tc.size {
+				t.Fatalf("want %d, got %d", tc.size, got)
+			}
+			if got := CurveName(pub); got != tc.curve {
+				t.Fatalf("want %q, got %q", tc.curve, got)
+			}
+
+			switch pub := pub.(type) {
+			case *rsa.PublicKey:
+				if pub.E != 65537 {
+					t.Fatalf("want exponent 65537, got %d", pub.E)
+				}
+			case *ecdsa.PublicKey:
+				if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+					t.Fatal("point is not on curve")
+				}
+			case ed25519.PublicKey:
+			default:
+				t.Fatalf("unexpected key type %T", pub)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyFromSSHOptions(t *testing.T) {
+	line := strings.TrimSpace(readTestdata(t, "ssh_ed25519.pub"))
+	want, err := ParsePublicKeyFromSSH(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "# comment\n\n" + `from="10.0.0.1",no-pty ` + line + "\n"
+	got, err := ParsePublicKeyFromSSH(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := ComparePublicKeys(want, got); !ok {
+		t.Fatal("keys do not match")
+	}
+}
+
+func TestParsePublicKeyFromSSHInvalid(t *testing.T) {
+	rsaBlob := sshBlob(t, "ssh_rsa.pub")
+	edBlob := sshBlob(t, "ssh_ed25519.pub")
+	ecBlob := sshBlob(t, "ssh_ecdsa_256.pub")
+
+	testCases := []struct {
+		name    string
+		keyType string
+		blob    []byte
+		wantErr string
+	}{
+		{"truncated RSA", "ssh-rsa", rsaBlob[:len(rsaBlob)-10], "truncated SSH key"},
+		{"truncated Ed25519", "ssh-ed25519", edBlob[:len(edBlob)-1], "truncated SSH key"},
+		{"truncated length", "ssh-ed25519", edBlob[:2], "truncated SSH key"},
+		{"trailing RSA", "ssh-rsa", append(clone(rsaBlob), 0), "trailing data"},
+		{"trailing ECDSA", "ecdsa-sha2-nistp256", append(clone(ecBlob), 0, 0, 0, 0), "trailing data"},
+		{"trailing Ed25519", "ssh-ed25519", append(clone(edBlob), 1), "trailing data"},
+		{"type mismatch", "ssh-rsa", edBlob, "SSH key types do not match"},
+		{
+			"curve mismatch", "ecdsa-sha2-nistp384",
+			sshWire([]byte("ecdsa-sha2-nistp384"), []byte("nistp256"), []byte{4}),
+			"type and curve do not match",
+		},
+		{
+			"RSA exponent 1", "ssh-rsa",
+			sshWire([]byte("ssh-rsa"), []byte{1}, []byte{0x00, 0xc3}),
+			"invalid SSH RSA exponent",
+		},
+		{
+			"RSA exponent 0", "ssh-rsa",
+			sshWire([]byte("ssh-rsa"), nil, []byte{0x00, 0xc3}),
+			"invalid SSH RSA exponent",
+		},
+		{
+			"RSA zero modulus", "ssh-rsa",
+			sshWire([]byte("ssh-rsa"), []byte{1, 0, 1}, nil),
+			"invalid SSH RSA modulus",
+		},
+		{
+			"RSA negative modulus", "ssh-rsa",
+			sshWire([]byte("ssh-rsa"), []byte{1, 0, 1}, []byte{0xc3}),
+			"negative SSH integer",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			line := tc.keyType + " " + base64.StdEncoding.EncodeToString(tc.blob) + " test"
+			_, err := ParsePublicKeyFromSSH(line)
+			if err == nil {
+				t.Fatalf("want error %q", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("want error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func sshBlob(t *testing.T, name string) []byte {
+	t.Helper()
+	fields := strings.Fields(readTestdata(t, name))
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blob
+}
+
+func sshWire(fields ...[]byte) []byte {
+	var res []byte
+	for _, f := range fields {
+		res = binary.BigEndian.AppendUint32(res, uint32(len(f)))
+		res = append(res, f...)
+	}
+	return res
+}
+
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
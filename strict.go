@@ -0,0 +1,93 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+)
+
+// ParseX509Strict certificate from a PEM formatted block.
+// Unlike ParseX509 it fails on any data around the block.
+func ParseX509Strict(s string) (*x509.Certificate, error) {
+	block, err := decodeStrict(s, "CERTIFICATE", "TRUSTED CERTIFICATE")
+	if err != nil {
+		return nil, err
+	}
+	return parseX509Block(block)
+}
+
+// ParseRSAStrict private key from a PEM formatted block.
+// Unlike ParseRSA it fails on any data around the block.
+func ParseRSAStrict(s string) (*rsa.PrivateKey, error) {
+	block, err := decodeStrict(s, "RSA PRIVATE KEY", "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parseRSA(block.Bytes)
+}
+
+// ParseECDSAStrict private key from a PEM formatted block.
+// Unlike ParseECDSA it fails on any data around the block.
+func ParseECDSAStrict(s string) (*ecdsa.PrivateKey, error) {
+	block, err := decodeStrict(s, "EC PRIVATE KEY", "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parseECDSA(block.Bytes)
+}
+
+// ParseEd25519Strict private key from a PEM formatted block.
+// Unlike ParseEd25519 it fails on any data around the block.
+func ParseEd25519Strict(s string) (ed25519.PrivateKey, error) {
+	block, err := decodeStrict(s, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parseEd25519(block.Bytes)
+}
+
+// ParsePrivateKeyStrict private key from a PEM formatted block.
+// Unlike ParsePrivateKey it fails on any data around the block or on an unexpected block type.
+func ParsePrivateKeyStrict(s string) (crypto.PrivateKey, error) {
+	block, err := decodeStrict(s, "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY", "DSA PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(block.Bytes)
+}
+
+// ParsePublicKeyStrict public key from a PEM formatted block.
+// Unlike ParsePublicKey it fails on any data around the block or on a non-public key block.
+func ParsePublicKeyStrict(s string) (crypto.PublicKey, error) {
+	block, err := decodeStrict(s, "PUBLIC KEY", "RSA PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	return parsePublicKey(block.Bytes)
+}
+
+// decodeStrict decodes a single PEM block of one of the given types.
+// Only whitespace is allowed around the block.
+func decodeStrict(s string, types ...string) (*pem.Block, error) {
+	data := []byte(NormalizePEM(s))
+	if !bytes.HasPrefix(data, []byte("-----BEGIN ")) {
+		return nil, ErrInvalidPEM
+	}
+
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return nil, parseErrorf("unexpected data after PEM block")
+	}
+
+	if err := checkPEMType(block, types...); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
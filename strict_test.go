@@ -0,0 +1,42 @@
+package certutil
+
+import (
+	"crypto/dsa"
+	"testing"
+)
+
+func TestParsePrivateKeyStrict(t *testing.T) {
+	for _, name := range []string{"rsa.pem", "ec256.pem", "ed25519.pem", "dsa.pem", "dsa_pkcs8.pem"} {
+		t.Run(name, func(t *testing.T) {
+			s := readTestdata(t, name)
+
+			priv, err := ParsePrivateKeyStrict(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := ComparePrivateKeys(priv, mustPrivateKey(t, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Fatal("key does not match ParsePrivateKey result")
+			}
+
+			if _, err := ParsePrivateKeyStrict(s + "trailing"); err == nil {
+				t.Fatal("want error for trailing data")
+			}
+		})
+	}
+
+	priv, err := ParsePrivateKeyStrict(readTestdata(t, "dsa.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := priv.(*dsa.PrivateKey); !ok {
+		t.Fatalf("want *dsa.PrivateKey, got %T", priv)
+	}
+
+	if _, err := ParsePrivateKeyStrict(readTestdata(t, "root.pem")); err == nil {
+		t.Fatal("want error for certificate")
+	}
+}
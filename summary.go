@@ -0,0 +1,36 @@
+package certutil
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// CertSummary is a JSON-friendly summary of a certificate.
+type CertSummary struct {
+	Subject           string    `json:"subject"`
+	Issuer            string    `json:"issuer"`
+	SerialNumber      string    `json:"serial_number"` // decimal, to not overflow JSON numbers.
+	NotBefore         time.Time `json:"not_before"`
+	NotAfter          time.Time `json:"not_after"`
+	DNSNames          []string  `json:"dns_names,omitempty"`
+	KeyAlgorithm      string    `json:"key_algorithm"`
+	KeySize           int       `json:"key_size"`
+	FingerprintSHA256 string    `json:"fingerprint_sha256"`
+	IsCA              bool      `json:"is_ca"`
+}
+
+// Summarize returns summary of the certificate.
+func Summarize(cert *x509.Certificate) CertSummary {
+	return CertSummary{
+		Subject:           SubjectString(cert),
+		Issuer:            IssuerString(cert),
+		SerialNumber:      SerialDecimal(cert),
+		NotBefore:         cert.NotBefore,
+		NotAfter:          cert.NotAfter,
+		DNSNames:          cert.DNSNames,
+		KeyAlgorithm:      KeyAlgorithm(cert.PublicKey),
+		KeySize:           KeySize(cert.PublicKey),
+		FingerprintSHA256: FingerprintSHA256(cert),
+		IsCA:              IsCA(cert),
+	}
+}
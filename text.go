@@ -0,0 +1,118 @@
+package certutil
+
+import (
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Text returns human-readable description of the certificate in a layout close to `openssl x509 -text`.
+// Only commonly used fields and extensions are included, signature value is omitted.
+func Text(cert *x509.Certificate) string {
+	var sb strings.Builder
+	line := func(indent int, format string, args ...interface{}) {
+		sb.WriteString(strings.Repeat("    ", indent))
+		fmt.Fprintf(&sb, format, args...)
+		sb.WriteByte('\n')
+	}
+
+	line(0, "Certificate:")
+	line(1, "Data:")
+	line(2, "Version: %d (0x%x)", cert.Version, cert.Version-1)
+	textSerial(line, cert)
+	line(2, "Signature Algorithm: %s", SignatureAlgorithmName(cert))
+	line(2, "Issuer: %s", IssuerString(cert))
+	line(2, "Validity")
+	line(3, "Not Before: %s", cert.NotBefore.UTC().Format(textTimeLayout))
+	line(3, "Not After : %s", cert.NotAfter.UTC().Format(textTimeLayout))
+	line(2, "Subject: %s", SubjectString(cert))
+	line(2, "Subject Public Key Info:")
+	line(3, "Public Key Algorithm: %s", KeyAlgorithm(cert.PublicKey))
+	line(4, "Public-Key: (%d bit)", KeySize(cert.PublicKey))
+	if curve := CurveName(cert.PublicKey); curve != "" {
+		line(4, "NIST CURVE: %s", curve)
+	}
+
+	var exts [][2]string
+	if usages := KeyUsages(cert); len(usages) > 0 {
+		exts = append(exts, [2]string{"Key Usage", strings.Join(usages, ", ")})
+	}
+	if usages := ExtKeyUsages(cert); len(usages) > 0 {
+		exts = append(exts, [2]string{"Extended Key Usage", strings.Join(usages, ", ")})
+	}
+	if cert.BasicConstraintsValid {
+		bc := "CA:FALSE"
+		if cert.IsCA {
+			bc = "CA:TRUE"
+			if cert.MaxPathLen > 0 || cert.MaxPathLenZero {
+				bc += fmt.Sprintf(", pathlen:%d", cert.MaxPathLen)
+			}
+		}
+		exts = append(exts, [2]string{"Basic Constraints", bc})
+	}
+	if id := SubjectKeyID(cert); id != "" {
+		exts = append(exts, [2]string{"Subject Key Identifier", id})
+	}
+	if id := AuthorityKeyID(cert); id != "" {
+		exts = append(exts, [2]string{"Authority Key Identifier", id})
+	}
+	if sans := textSANs(cert); len(sans) > 0 {
+		exts = append(exts, [2]string{"Subject Alternative Name", strings.Join(sans, ", ")})
+	}
+
+	if len(exts) > 0 {
+		line(2, "X509v3 extensions:")
+		for _, ext := range exts {
+			line(3, "X509v3 %s:", ext[0])
+			line(4, "%s", ext[1])
+		}
+	}
+	return sb.String()
+}
+
+// textSerial prints serial number in decimal if it fits into int64, as a hex dump otherwise.
+func textSerial(line func(indent int, format string, args ...interface{}), cert *x509.Certificate) {
+	serial := cert.SerialNumber
+	if serial == nil {
+		line(2, "Serial Number:")
+		return
+	}
+
+	if abs := new(big.Int).Abs(serial); abs.IsInt64() {
+		sign := ""
+		if serial.Sign() < 0 {
+			sign = "-"
+		}
+		line(2, "Serial Number: %s%d (%s0x%x)", sign, abs.Int64(), sign, abs.Int64())
+		return
+	}
+
+	line(2, "Serial Number:")
+	if serial.Sign() < 0 {
+		line(3, " (Negative)%s", strings.TrimPrefix(SerialHex(cert), "-"))
+		return
+	}
+	line(3, "%s", SerialHex(cert))
+}
+
+// textTimeLayout is the time format of OpenSSL, like "Jan  2 15:04:05 2006 GMT".
+const textTimeLayout = "Jan _2 15:04:05 2006 GMT"
+
+// textSANs returns SANs prefixed by their type as OpenSSL prints them.
+func textSANs(cert *x509.Certificate) []string {
+	var sans []string
+	for _, name := range cert.DNSNames {
+		sans = append(sans, "DNS:"+name)
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, "IP Address:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, "URI:"+uri.String())
+	}
+	return sans
+}
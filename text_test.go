@@ -0,0 +1,47 @@
+package certutil
+
+import (
+	"crypto/x509"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestTextSerial(t *testing.T) {
+	// want values are from `openssl x509 -noout -text`.
+	testCases := []struct {
+		file string
+		want string
+	}{
+		{"leaf.pem", "        Serial Number: 3 (0x3)\n"},
+		{"serial_128.pem", "        Serial Number: 128 (0x80)\n"},
+		{"serial_u64.pem", "        Serial Number:\n            ff:ff:ff:ff:ff:ff:ff:ff\n"},
+		{"serial_high.pem", "        Serial Number:\n            8f:1a:2b:3c:4d:5e:6f:70:81:92\n"},
+	}
+
+	for _, tc := range testCases {
+		text := Text(mustCertificate(t, tc.file))
+		want := "Certificate:\n    Data:\n        Version: 3 (0x2)\n" + tc.want + "        Signature Algorithm:"
+		if !strings.HasPrefix(text, want) {
+			t.Errorf("%s: want prefix\n%s\ngot\n%s", tc.file, want, text)
+		}
+	}
+
+	// Go rejects negative serials on parse, so these are built directly.
+	serials := []struct {
+		serial *big.Int
+		want   string
+	}{
+		{big.NewInt(-5), "        Serial Number: -5 (-0x5)\n"},
+		{
+			new(big.Int).Neg(new(big.Int).SetBytes([]byte{0x8f, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e, 0x6f, 0x70, 0x81, 0x92})),
+			"        Serial Number:\n             (Negative)8f:1a:2b:3c:4d:5e:6f:70:81:92\n",
+		},
+	}
+	for _, tc := range serials {
+		text := Text(&x509.Certificate{Version: 3, SerialNumber: tc.serial})
+		if !strings.Contains(text, tc.want) {
+			t.Errorf("%v: want\n%s\ngot\n%s", tc.serial, tc.want, text)
+		}
+	}
+}
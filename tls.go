@@ -0,0 +1,65 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TLSCertificate returns tls.Certificate for the leaf certificate, its intermediates and private key.
+// Intermediates are ordered from the leaf issuer upwards, unrelated ones are appended as is.
+// Returns an error if private key does not match the leaf.
+func TLSCertificate(leaf *x509.Certificate, intermediates []*x509.Certificate, priv crypto.PrivateKey) (tls.Certificate, error) {
+	ok, err := KeysMatch(priv, leaf)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if !ok {
+		return tls.Certificate{}, errors.New("certutil: private key does not match certificate")
+	}
+
+	chain := make([][]byte, 0, 1+len(intermediates))
+	chain = append(chain, leaf.Raw)
+	for _, cert := range issuerOrder(leaf, intermediates) {
+		chain = append(chain, cert.Raw)
+	}
+
+	tlsCert := tls.Certificate{
+		Certificate: chain,
+		PrivateKey:  priv,
+		Leaf:        leaf,
+	}
+	return tlsCert, nil
+}
+
+// UsableForServerAuth returns an error if certificate cannot be used by a TLS server for the host at the given time.
+// Certificate must be within its validity period, have server authentication or no extended key usage,
+// allow digital signature or key encipherment if key usage is set and be valid for the host.
+// Chain of trust is not verified.
+func UsableForServerAuth(cert *x509.Certificate, host string, at time.Time) error {
+	switch {
+	case at.Before(cert.NotBefore):
+		return fmt.Errorf("certutil: certificate is not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	case at.After(cert.NotAfter):
+		return fmt.Errorf("certutil: certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	if len(cert.ExtKeyUsage) > 0 || len(cert.UnknownExtKeyUsage) > 0 {
+		if !HasExtKeyUsage(cert, x509.ExtKeyUsageServerAuth) {
+			return errors.New("certutil: certificate has no server authentication extended key usage")
+		}
+	}
+
+	const serverUsages = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	if cert.KeyUsage != 0 && cert.KeyUsage&serverUsages == 0 {
+		return errors.New("certutil: certificate key usage does not allow digital signature or key encipherment")
+	}
+
+	if err := cert.VerifyHostname(host); err != nil {
+		return fmt.Errorf("certutil: verify hostname: %w", err)
+	}
+	return nil
+}
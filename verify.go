@@ -0,0 +1,232 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// VerifyOption configures VerifyChain.
+type VerifyOption func(opts *x509.VerifyOptions)
+
+// WithTime sets the time at which the chain is verified. Default is the current time.
+func WithTime(t time.Time) VerifyOption {
+	return func(opts *x509.VerifyOptions) {
+		opts.CurrentTime = t
+	}
+}
+
+// WithKeyUsages sets the accepted extended key usages. Default is server authentication.
+func WithKeyUsages(usages ...x509.ExtKeyUsage) VerifyOption {
+	return func(opts *x509.VerifyOptions) {
+		opts.KeyUsages = usages
+	}
+}
+
+// VerifyChain verifies leaf certificate against intermediates and roots.
+// When roots are empty the system roots are used.
+func VerifyChain(leaf *x509.Certificate, intermediates, roots []*x509.Certificate, opts ...VerifyOption) error {
+	vopts := x509.VerifyOptions{
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range intermediates {
+		vopts.Intermediates.AddCert(cert)
+	}
+	if len(roots) > 0 {
+		vopts.Roots = x509.NewCertPool()
+		for _, cert := range roots {
+			vopts.Roots.AddCert(cert)
+		}
+	}
+
+	for _, opt := range opts {
+		opt(&vopts)
+	}
+
+	_, err := leaf.Verify(vopts)
+	return err
+}
+
+// CertPoolFromPEM returns x509.CertPool with all certificates from PEM formatted blocks.
+// Unlike x509.CertPool.AppendCertsFromPEM it returns an error if no certificates were found.
+func CertPoolFromPEM(s string) (*x509.CertPool, error) {
+	certs, err := ParseX509Chain(s)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// IssuerMatches reports whether child issuer is byte-for-byte equal to parent subject.
+// This is a cheap prefilter, not a proof of issuance: it must be followed by VerifySignedBy.
+func IssuerMatches(child, parent *x509.Certificate) bool {
+	return bytes.Equal(child.RawIssuer, parent.RawSubject)
+}
+
+// VerifySignedBy verifies that child certificate is signed by the parent.
+func VerifySignedBy(child, parent *x509.Certificate) error {
+	if err := child.CheckSignatureFrom(parent); err != nil {
+		return fmt.Errorf("certutil: %q is not signed by %q: %w", child.Subject, parent.Subject, err)
+	}
+	return nil
+}
+
+// OrderChain returns certificates ordered from the leaf to the root.
+// Parent is matched by authority/subject key ID when present, by issuer/subject name otherwise.
+// Returns an error if certificates do not form a single chain.
+func OrderChain(certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("certutil: no certificates")
+	}
+
+	var leaf *x509.Certificate
+	for i, cert := range certs {
+		isParent := false
+		for j, other := range certs {
+			if i != j && issuedBy(other, cert) {
+				isParent = true
+				break
+			}
+		}
+		if isParent {
+			continue
+		}
+		if leaf != nil {
+			return nil, errors.New("certutil: more than one leaf certificate")
+		}
+		leaf = cert
+	}
+	if leaf == nil {
+		return nil, errors.New("certutil: no leaf certificate")
+	}
+
+	rest := make([]*x509.Certificate, 0, len(certs)-1)
+	for _, cert := range certs {
+		if cert != leaf {
+			rest = append(rest, cert)
+		}
+	}
+
+	ordered := append([]*x509.Certificate{leaf}, issuerOrder(leaf, rest)...)
+	for i := 1; i < len(ordered); i++ {
+		if !issuedBy(ordered[i-1], ordered[i]) {
+			return nil, errors.New("certutil: certificates do not form a single chain")
+		}
+	}
+	return ordered, nil
+}
+
+// ChainComplete reports whether chain reaches one of the roots, starting from the leaf chain[0].
+// Other certificates may be in any order. When roots are nil the system roots are used.
+// If the chain is incomplete the last certificate whose issuer cannot be found is returned.
+func ChainComplete(chain []*x509.Certificate, roots *x509.CertPool) (bool, *x509.Certificate, error) {
+	if len(chain) == 0 {
+		return false, nil, errors.New("certutil: no certificates")
+	}
+
+	rest := append([]*x509.Certificate(nil), chain[1:]...)
+	for curr := chain[0]; ; {
+		if signedByRoot(curr, roots) {
+			return true, nil, nil
+		}
+
+		idx := -1
+		for i, cert := range rest {
+			if issuedBy(curr, cert) && curr.CheckSignatureFrom(cert) == nil {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return false, curr, nil
+		}
+
+		curr = rest[idx]
+		rest = append(rest[:idx], rest[idx+1:]...)
+	}
+}
+
+// signedByRoot reports whether cert is one of the roots or is directly signed by one of them.
+// Certificate expiration and key usages are ignored.
+func signedByRoot(cert *x509.Certificate, roots *x509.CertPool) bool {
+	at := time.Now()
+	if !IsValidAt(cert, at) {
+		at = cert.NotAfter
+	}
+
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: at,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// MaxPathLen returns path length constraint of the CA certificate.
+// Reports false if certificate is not a CA or constraint is not set.
+func MaxPathLen(cert *x509.Certificate) (int, bool) {
+	if !IsCA(cert) {
+		return 0, false
+	}
+	// zero is ambiguous without MaxPathLenZero, negative means unset.
+	if cert.MaxPathLen > 0 || (cert.MaxPathLen == 0 && cert.MaxPathLenZero) {
+		return cert.MaxPathLen, true
+	}
+	return 0, false
+}
+
+// PathLenOK checks that no CA in the chain ordered from the leaf to the root
+// has more intermediate CAs below it than its path length constraint allows.
+func PathLenOK(chain []*x509.Certificate) error {
+	for i := 1; i < len(chain); i++ {
+		maxLen, ok := MaxPathLen(chain[i])
+		if !ok {
+			continue
+		}
+		// certificates between the leaf and chain[i].
+		if below := i - 1; below > maxLen {
+			return fmt.Errorf("certutil: path length %d of %q exceeds its constraint %d", below, chain[i].Subject, maxLen)
+		}
+	}
+	return nil
+}
+
+// issuedBy reports whether parent looks like the issuer of child.
+// This is not a signature check.
+func issuedBy(child, parent *x509.Certificate) bool {
+	if len(child.AuthorityKeyId) > 0 && len(parent.SubjectKeyId) > 0 {
+		return bytes.Equal(child.AuthorityKeyId, parent.SubjectKeyId)
+	}
+	return IssuerMatches(child, parent)
+}
+
+// issuerOrder returns certs ordered by issuer starting from the leaf.
+func issuerOrder(leaf *x509.Certificate, certs []*x509.Certificate) []*x509.Certificate {
+	rest := append([]*x509.Certificate(nil), certs...)
+	res := make([]*x509.Certificate, 0, len(certs))
+
+	for curr := leaf; len(rest) > 0; {
+		idx := -1
+		for i, cert := range rest {
+			if issuedBy(curr, cert) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			break
+		}
+
+		curr = rest[idx]
+		res = append(res, curr)
+		rest = append(rest[:idx], rest[idx+1:]...)
+	}
+	return append(res, rest...)
+}
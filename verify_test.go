@@ -0,0 +1,96 @@
+package certutil
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+)
+
+func TestMaxPathLen(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cert   *x509.Certificate
+		want   int
+		wantOK bool
+	}{
+		{"root without constraint", mustCertificate(t, "root.pem"), 0, false},
+		{"intermediate with pathlen 0", mustCertificate(t, "int.pem"), 0, true},
+		{"leaf", mustCertificate(t, "leaf.pem"), 0, false},
+		{"zero without MaxPathLenZero", newCA("ca", 0, false), 0, false},
+		{"zero with MaxPathLenZero", newCA("ca", 0, true), 0, true},
+		{"unset", newCA("ca", -1, false), 0, false},
+		{"pathlen 2", newCA("ca", 2, false), 2, true},
+		{"not a CA", &x509.Certificate{MaxPathLen: 3}, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := MaxPathLen(tc.cert)
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("want (%d, %v), got (%d, %v)", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestPathLenOK(t *testing.T) {
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}
+
+	testCases := []struct {
+		name    string
+		chain   []*x509.Certificate
+		wantErr bool
+	}{
+		{
+			"real chain",
+			[]*x509.Certificate{mustCertificate(t, "leaf.pem"), mustCertificate(t, "int.pem"), mustCertificate(t, "root.pem")},
+			false,
+		},
+		{"only leaf", []*x509.Certificate{leaf}, false},
+		{
+			"pathlen 0 directly above leaf",
+			[]*x509.Certificate{leaf, newCA("int", 0, true), newCA("root", -1, false)},
+			false,
+		},
+		{
+			"pathlen 0 exceeded",
+			[]*x509.Certificate{leaf, newCA("sub", -1, false), newCA("int", 0, true), newCA("root", -1, false)},
+			true,
+		},
+		{
+			// without MaxPathLenZero zero means no constraint.
+			"zero without MaxPathLenZero",
+			[]*x509.Certificate{leaf, newCA("sub", -1, false), newCA("int", 0, false), newCA("root", -1, false)},
+			false,
+		},
+		{
+			"root pathlen 1 exceeded",
+			[]*x509.Certificate{leaf, newCA("sub", -1, false), newCA("int", -1, false), newCA("root", 1, false)},
+			true,
+		},
+		{
+			"root pathlen 1",
+			[]*x509.Certificate{leaf, newCA("int", -1, false), newCA("root", 1, false)},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := PathLenOK(tc.chain)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func newCA(name string, maxPathLen int, maxPathLenZero bool) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		MaxPathLen:            maxPathLen,
+		MaxPathLenZero:        maxPathLenZero,
+	}
+}